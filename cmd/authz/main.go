@@ -25,7 +25,7 @@ func main() {
 	// set default logger
 	slog.SetDefault(logger)
 
-	// admin.Run(cfg)
+	// authz.Run starts the admin server next to the API
 	authz.Run(cfg)
 }
 
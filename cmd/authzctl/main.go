@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"time"
+
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/volvo-cars/connect-access-control/internal/config"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/roles"
+)
+
+const (
+	rolesFile      = "config/roles.yaml"
+	commandTimeout = 30 * time.Second
+)
+
+const usage = `usage: authzctl <command> [flags]
+
+commands:
+  roles sync    compare config/roles.yaml against the PLUMS role catalog
+`
+
+// exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitDrift = 2
+)
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	slog.SetDefault(logger)
+
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) < 2 || args[0] != "roles" || args[1] != "sync" {
+		fmt.Fprint(os.Stderr, usage)
+		return exitError
+	}
+
+	if err := rolesSync(args[2:]); err != nil {
+		if errors.Is(err, errDrift) {
+			return exitDrift
+		}
+
+		fmt.Fprintln(os.Stderr, err.Error())
+		return exitError
+	}
+
+	return exitOK
+}
+
+var errDrift = errors.New("roles drifted from plums")
+
+func rolesSync(args []string) error {
+	flags := flag.NewFlagSet("roles sync", flag.ContinueOnError)
+	check := flags.Bool("check", false, "exit with a non-zero code when roles.yaml drifted from PLUMS")
+	write := flags.Bool("write", false, "rewrite roles.yaml to match PLUMS")
+	file := flags.String("file", "", "roles file, defaults to $IAM_ROOT_DIR/"+rolesFile)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	filePath := *file
+	if filePath == "" {
+		cfg, err := config.New()
+		if err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		filePath = path.Join(cfg.IAM.RootDir, rolesFile)
+	}
+
+	local, err := roles.Read(filePath)
+	if err != nil {
+		return err
+	}
+
+	plumsCfg, err := plums.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load plums config: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	remote, err := plums.New(plumsCfg, noopCollector{}).GetRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	report := roles.Diff(local, remote)
+	printReport(filePath, report)
+
+	if *write && report.HasDrift() {
+		if err := roles.Write(filePath, roles.Apply(local, report)); err != nil {
+			return err
+		}
+		fmt.Printf("[√] file://%s updated\n", filePath)
+	}
+
+	if *check && report.HasDrift() {
+		return errDrift
+	}
+
+	return nil
+}
+
+func printReport(filePath string, report roles.Report) {
+	if !report.HasDrift() {
+		fmt.Printf("[√] file://%s is in sync with plums\n", filePath)
+		return
+	}
+
+	fmt.Printf("[x] file://%s drifted from plums\n", filePath)
+	for _, role := range report.Added {
+		fmt.Printf("	[+] %s	:: %s\n", role.ID, role.Name)
+	}
+
+	for _, role := range report.Removed {
+		fmt.Printf("	[-] %s	:: %s\n", role.ID, role.Name)
+	}
+
+	for _, change := range report.Changed {
+		fmt.Printf("	[~] %s	:: %s\n", change.ID, change.Field)
+		fmt.Printf("		local	:: %s\n", change.Local)
+		fmt.Printf("		plums	:: %s\n", change.Remote)
+	}
+}
+
+// noopCollector drops the outgoing request metrics, there is nothing scraping a one-shot command.
+type noopCollector struct{}
+
+func (noopCollector) ObserveRequestTimeWithOp(_, _, _, _ string, _ int, _ time.Duration) {}
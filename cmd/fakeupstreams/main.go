@@ -0,0 +1,47 @@
+package main
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/fakeupstreams"
+)
+
+const readHeaderTimeout = 10 * time.Second
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	port := getEnv("FAKE_UPSTREAMS_PORT", "8090")
+	fixturesFile := getEnv("FAKE_UPSTREAMS_FIXTURES", "docker/fakeupstreams/fixtures.yaml")
+
+	fixtures, err := fakeupstreams.LoadFixtures(fixturesFile)
+	if err != nil {
+		slog.Error("failed to load fixtures", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	server := &http.Server{
+		Addr:              net.JoinHostPort("", port),
+		Handler:           fakeupstreams.New(fixtures).Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	slog.Info("fake upstreams listening", slog.String("port", port), slog.String("fixtures", fixturesFile))
+	if err := server.ListenAndServe(); err != nil {
+		slog.Error("fake upstreams failed to serve", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+
+	return fallback
+}
@@ -17,7 +17,7 @@ const docTemplate = `{
     "paths": {
         "/iam/clients": {
             "get": {
-                "description": "get all clients",
+                "description": "get all clients, sorted and paged",
                 "consumes": [
                     "application/json"
                 ],
@@ -28,11 +28,50 @@ const docTemplate = `{
                     "clients"
                 ],
                 "summary": "get clients",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 1000, the whole list is returned without a limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "id",
+                        "description": "Sort field, one of id or name, prefixed with - for a descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only clients depending on the scope",
+                        "name": "dependant_scope",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/ClientsResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
                     "500": {
@@ -44,9 +83,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/iam/clients/{id}": {
+        "/iam/clients/{clientID}/users/{cdsid}/access": {
             "get": {
-                "description": "get client by ID",
+                "description": "get the access of a user for the dependant scopes of a client, together with the client",
                 "consumes": [
                     "application/json"
                 ],
@@ -56,21 +95,60 @@ const docTemplate = `{
                 "tags": [
                     "clients"
                 ],
-                "summary": "get client",
+                "summary": "get client user access",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Client ID",
-                        "name": "id",
+                        "name": "clientID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User CDSID",
+                        "name": "cdsid",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Scope key, defaults to all dependant scopes of the client",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Evaluate only the partner context with this PLUMS or cache-manager partner ID",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Evaluate only the primary partner context",
+                        "name": "primary",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ClientResponse"
+                            "$ref": "#/definitions/ClientUserAccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            },
+                            "X-Data-Stale": {
+                                "type": "string",
+                                "description": "set to true when the user data is served from a stale cache entry"
+                            }
                         }
                     },
                     "400": {
@@ -79,6 +157,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -90,32 +174,21 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/iam/roles": {
-            "get": {
-                "description": "get all roles",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "roles"
-                ],
-                "summary": "get roles",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
-                            "$ref": "#/definitions/RolesResponse"
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -123,9 +196,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/iam/roles/{id}": {
+        "/iam/clients/{id}": {
             "get": {
-                "description": "get role by ID",
+                "description": "get client by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -133,13 +206,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "clients"
                 ],
-                "summary": "get role",
+                "summary": "get client",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Role ID",
+                        "description": "Client ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -149,7 +222,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/RoleResponse"
+                            "$ref": "#/definitions/ClientResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            }
                         }
                     },
                     "400": {
@@ -173,38 +252,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/iam/scopes": {
+        "/iam/events": {
             "get": {
-                "description": "get all scopes",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "stream the changes of the IAM configuration as server-sent events. A config.revision event carrying a ConfigChange is sent for every new revision. Reconnecting with the Last-Event-ID header replays the missed events, or a single config.reset event when they are no longer buffered, after which everything derived from the configuration must be fetched again.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "scopes"
+                    "events"
+                ],
+                "summary": "configuration events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the last received event",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
                 ],
-                "summary": "get scopes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ScopesResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
+                            "$ref": "#/definitions/ConfigChange"
                         }
                     }
                 }
             }
         },
-        "/iam/scopes/{scopeKey}": {
+        "/iam/me": {
             "get": {
-                "description": "get scope by key",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get the user of the bearer token",
                 "consumes": [
                     "application/json"
                 ],
@@ -212,23 +295,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "scopes"
+                    "me"
                 ],
-                "summary": "get scope",
+                "summary": "get me",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Scope key",
-                        "name": "scopeKey",
-                        "in": "path",
-                        "required": true
+                        "description": "Comma separated sparse fieldset, nested fields are selected with a dot, e.g. id,cdsid,partners.id",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ScopeResponse"
+                            "$ref": "#/definitions/UserResponse"
+                        },
+                        "headers": {
+                            "X-Data-Stale": {
+                                "type": "string",
+                                "description": "set to true when the user data is served from a stale cache entry"
+                            }
                         }
                     },
                     "400": {
@@ -237,6 +325,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -248,13 +342,36 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/iam/scopes/{scopeKey}/mappings": {
+        "/iam/me/access": {
             "get": {
-                "description": "get all role mappings for a scope",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get all access for the user of the bearer token",
                 "consumes": [
                     "application/json"
                 ],
@@ -262,23 +379,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "scopes"
+                    "me"
                 ],
-                "summary": "get role mappings",
+                "summary": "get my access",
                 "parameters": [
                     {
-                        "type": "string",
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
                         "description": "Scope key",
-                        "name": "scopeKey",
-                        "in": "path",
+                        "name": "scope",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Evaluate only the partner context with this PLUMS or cache-manager partner ID",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Evaluate only the primary partner context",
+                        "name": "primary",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/RoleMappingsResponse"
+                            "$ref": "#/definitions/UserAccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            },
+                            "X-Data-Stale": {
+                                "type": "string",
+                                "description": "set to true when the user data is served from a stale cache entry"
+                            }
                         }
                     },
                     "400": {
@@ -287,18 +430,54 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/iam/scopes/{scopeKey}/mappings/{roleID}": {
+        "/iam/roles": {
             "get": {
-                "description": "get role mapping by scope and role ID",
+                "description": "get all roles, sorted and paged",
                 "consumes": [
                     "application/json"
                 ],
@@ -306,30 +485,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "scopes"
+                    "roles"
                 ],
-                "summary": "get role mapping",
+                "summary": "get roles",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 1000, the whole list is returned without a limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Scope key",
-                        "name": "scopeKey",
-                        "in": "path",
-                        "required": true
+                        "default": "id",
+                        "description": "Sort field, one of id or name, prefixed with - for a descending order",
+                        "name": "sort",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Role ID",
-                        "name": "roleID",
-                        "in": "path",
-                        "required": true
+                        "description": "Only roles whose name contains the text, ignoring case",
+                        "name": "name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/RoleMappingResponse"
+                            "$ref": "#/definitions/RolesResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            }
                         }
                     },
                     "400": {
@@ -338,12 +534,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -353,9 +543,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/iam/users/{cdsid}": {
+        "/iam/roles/{id}": {
             "get": {
-                "description": "get user by CDSID",
+                "description": "get role by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -363,14 +553,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "roles"
                 ],
-                "summary": "get user",
+                "summary": "get role",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User CDSID",
-                        "name": "cdsid",
+                        "description": "Role ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -379,7 +569,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/UserResponse"
+                            "$ref": "#/definitions/RoleResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            }
                         }
                     },
                     "400": {
@@ -403,9 +599,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/iam/users/{cdsid}/access": {
+        "/iam/scopes": {
             "get": {
-                "description": "get all access for a user",
+                "description": "get all scopes, sorted and paged",
                 "consumes": [
                     "application/json"
                 ],
@@ -413,27 +609,904 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "scopes"
                 ],
-                "summary": "get user access",
+                "summary": "get scopes",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 1000, the whole list is returned without a limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "User CDSID",
-                        "name": "cdsid",
-                        "in": "path",
-                        "required": true
+                        "default": "key",
+                        "description": "Sort field, one of key, label or type, prefixed with - for a descending order",
+                        "name": "sort",
+                        "in": "query"
                     },
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Scope key",
-                        "name": "scope",
-                        "in": "query",
-                        "required": true
+                        "type": "string",
+                        "description": "Only scopes of the type, functionality or data",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ScopesResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iam/scopes/{scopeKey}": {
+            "get": {
+                "description": "get scope by key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scopes"
+                ],
+                "summary": "get scope",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scope key",
+                        "name": "scopeKey",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ScopeResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iam/scopes/{scopeKey}/mappings": {
+            "get": {
+                "description": "get all role mappings for a scope, sorted and paged. Filtering by market or partner type narrows the mappings down to the entries applying to it, entries without a filter apply to all.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scopes"
+                ],
+                "summary": "get role mappings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scope key",
+                        "name": "scopeKey",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 1000, the whole list is returned without a limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "id",
+                        "description": "Sort field, id, prefixed with - for a descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the mapping entries applying to the market",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the mapping entries applying to the partner type",
+                        "name": "partner_type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/RoleMappingsResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iam/scopes/{scopeKey}/mappings/{roleID}": {
+            "get": {
+                "description": "get role mapping by scope and role ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scopes"
+                ],
+                "summary": "get role mapping",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scope key",
+                        "name": "scopeKey",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "roleID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/RoleMappingResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iam/token": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "mint a short-lived token carrying the access of the user for the scopes of the calling client, verified with the keys of /.well-known/jwks.json",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "token"
+                ],
+                "summary": "create token",
+                "parameters": [
+                    {
+                        "description": "User and scopes of the token, default to the subject of the bearer token and the dependant scopes of the client",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/TokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iam/users": {
+            "get": {
+                "description": "get user by email, PLUMS user ID or identity provider user ID, exactly one user key must be set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "find user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User CDSID",
+                        "name": "cdsid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PLUMS user ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identity provider, e.g. AzureAD_VCC, required with provider_user_id",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID at the identity provider, e.g. the Azure object ID",
+                        "name": "provider_user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sparse fieldset, nested fields are selected with a dot, e.g. id,cdsid,partners.id",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserResponse"
+                        },
+                        "headers": {
+                            "X-Data-Stale": {
+                                "type": "string",
+                                "description": "set to true when the user data is served from a stale cache entry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iam/users/access": {
+            "get": {
+                "description": "get all access for a user found by email, PLUMS user ID or identity provider user ID, exactly one user key must be set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "find user access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User CDSID",
+                        "name": "cdsid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PLUMS user ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identity provider, e.g. AzureAD_VCC, required with provider_user_id",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID at the identity provider, e.g. the Azure object ID",
+                        "name": "provider_user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Scope key",
+                        "name": "scope",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Evaluate only the partner context with this PLUMS or cache-manager partner ID",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Evaluate only the primary partner context",
+                        "name": "primary",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserAccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            },
+                            "X-Data-Stale": {
+                                "type": "string",
+                                "description": "set to true when the user data is served from a stale cache entry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iam/users/access:batch": {
+            "post": {
+                "description": "get the access of many users for the scopes, streamed as one JSON object per line in the order the users complete. Users that fail carry the error instead of their accesses, the stream itself always answers 200 once it started.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "batch user access",
+                "parameters": [
+                    {
+                        "description": "Users and scopes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UserAccessBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserAccessBatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iam/users/check": {
+            "get": {
+                "description": "check whether a user found by email, PLUMS user ID or identity provider user ID holds a permission group of a scope for a target partner, distributor or market, exactly one user key and one target must be set",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "find user and check access to a target",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User CDSID",
+                        "name": "cdsid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PLUMS user ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Identity provider, e.g. AzureAD_VCC, required with provider_user_id",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID at the identity provider, e.g. the Azure object ID",
+                        "name": "provider_user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scope key",
+                        "name": "scope",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Permission group, any permission group of the scope when empty, must be empty for data scopes",
+                        "name": "permission_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target partner, the PARMA code of PARMA partners and the ID of any other partner",
+                        "name": "partner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of the target partner, defaults to PARMA",
+                        "name": "partner_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target distributor ID",
+                        "name": "distributor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target market",
+                        "name": "market",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DecisionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iam/users/{cdsid}": {
+            "get": {
+                "description": "get user by CDSID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "get user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User CDSID",
+                        "name": "cdsid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated sparse fieldset, nested fields are selected with a dot, e.g. id,cdsid,partners.id",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UserResponse"
+                        },
+                        "headers": {
+                            "X-Data-Stale": {
+                                "type": "string",
+                                "description": "set to true when the user data is served from a stale cache entry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iam/users/{cdsid}/access": {
+            "get": {
+                "description": "get all access for a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "get user access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User CDSID",
+                        "name": "cdsid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Scope key",
+                        "name": "scope",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Evaluate only the partner context with this PLUMS or cache-manager partner ID",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Evaluate only the primary partner context",
+                        "name": "primary",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -441,6 +1514,127 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/UserAccessResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+                            },
+                            "X-Data-Stale": {
+                                "type": "string",
+                                "description": "set to true when the user data is served from a stale cache entry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/iam/users/{cdsid}/check": {
+            "get": {
+                "description": "check whether a user holds a permission group of a scope for a target partner, distributor or market, exactly one target must be set. NSC contexts also administer the partners under their distributor and in their market, data scopes are decided by their access domains.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "check user access to a target",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User CDSID",
+                        "name": "cdsid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scope key",
+                        "name": "scope",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Permission group, any permission group of the scope when empty, must be empty for data scopes",
+                        "name": "permission_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target partner, the PARMA code of PARMA partners and the ID of any other partner",
+                        "name": "partner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of the target partner, defaults to PARMA",
+                        "name": "partner_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target distributor ID",
+                        "name": "distributor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target market",
+                        "name": "market",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DecisionResponse"
                         }
                     },
                     "400": {
@@ -449,6 +1643,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -460,12 +1660,114 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
+        "AccessDomain": {
+            "type": "object",
+            "properties": {
+                "distributors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "markets": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "partners": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "Address": {
+            "type": "object",
+            "properties": {
+                "address_line_1": {
+                    "type": "string"
+                },
+                "address_line_2": {
+                    "type": "string"
+                },
+                "address_line_3": {
+                    "type": "string"
+                },
+                "address_line_4": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "country_code": {
+                    "type": "string"
+                },
+                "country_name": {
+                    "type": "string"
+                },
+                "district": {
+                    "type": "string"
+                },
+                "language_code": {
+                    "type": "string"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "ChangeSet": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "changed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "Client": {
             "type": "object",
             "properties": {
@@ -497,6 +1799,34 @@ const docTemplate = `{
             "properties": {
                 "data": {
                     "$ref": "#/definitions/Client"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
+                }
+            }
+        },
+        "ClientUserAccess": {
+            "type": "object",
+            "properties": {
+                "accesses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UserAccess"
+                    }
+                },
+                "client": {
+                    "$ref": "#/definitions/Client"
+                }
+            }
+        },
+        "ClientUserAccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/ClientUserAccess"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
                 }
             }
         },
@@ -508,6 +1838,48 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/Client"
                     }
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
+                }
+            }
+        },
+        "ConfigChange": {
+            "type": "object",
+            "properties": {
+                "digest": {
+                    "description": "Digest is the hash of the configuration, the same configuration has the same digest on every instance.",
+                    "type": "string"
+                },
+                "mappings": {
+                    "description": "Mappings are keyed by scope key and role ID, e.g. user-admin/\u003crole ID\u003e.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ChangeSet"
+                        }
+                    ]
+                },
+                "permission_groups": {
+                    "description": "PermissionGroups are keyed by scope key and permission group key, e.g. user-admin/view_user_details.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ChangeSet"
+                        }
+                    ]
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "roles": {
+                    "$ref": "#/definitions/ChangeSet"
+                },
+                "scopes": {
+                    "description": "Scopes are keyed by scope key and Roles by role ID.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ChangeSet"
+                        }
+                    ]
                 }
             }
         },
@@ -517,6 +1889,13 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "inherited": {
+                    "description": "Inherited is set on contexts derived from the distributor of an NSC context, InheritedFrom is the NSC ID.",
+                    "type": "boolean"
+                },
+                "inherited_from": {
+                    "type": "string"
+                },
                 "tag": {
                     "type": "string"
                 },
@@ -525,6 +1904,31 @@ const docTemplate = `{
                 }
             }
         },
+        "Decision": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean"
+                },
+                "context": {
+                    "$ref": "#/definitions/Context"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "DecisionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/Decision"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
+                }
+            }
+        },
         "Error": {
             "type": "object",
             "properties": {
@@ -533,6 +1937,10 @@ const docTemplate = `{
                 },
                 "message": {
                     "type": "string"
+                },
+                "reason": {
+                    "description": "Reason is a stable, machine readable error code, e.g. upstream_timeout.",
+                    "type": "string"
                 }
             }
         },
@@ -567,12 +1975,35 @@ const docTemplate = `{
                 }
             }
         },
+        "Identity": {
+            "type": "object",
+            "properties": {
+                "account_name": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "provider_user_id": {
+                    "type": "string"
+                }
+            }
+        },
         "Mapping": {
             "type": "object",
             "properties": {
+                "domains": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "filter": {
                     "$ref": "#/definitions/Filter"
                 },
+                "inherit": {
+                    "type": "boolean"
+                },
                 "permission_groups": {
                     "type": "array",
                     "items": {
@@ -581,18 +2012,56 @@ const docTemplate = `{
                 }
             }
         },
+        "Meta": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "description": "Page is set on lists, it describes the returned part of the list.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Page"
+                        }
+                    ]
+                },
+                "stale": {
+                    "description": "Stale is set when the user data is served from the cache past its TTL, e.g. while PLUMS is unavailable.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "Page": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "description": "Limit is the requested page size, the whole list from Offset on is returned without a limit.",
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "Partner": {
             "type": "object",
             "properties": {
                 "active": {
                     "type": "boolean"
                 },
+                "address": {
+                    "$ref": "#/definitions/Address"
+                },
                 "distributor_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
+                "is_primary": {
+                    "type": "boolean"
+                },
                 "market": {
                     "type": "string"
                 },
@@ -663,6 +2132,9 @@ const docTemplate = `{
             "properties": {
                 "data": {
                     "$ref": "#/definitions/RoleMapping"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
                 }
             }
         },
@@ -674,6 +2146,9 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/RoleMapping"
                     }
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
                 }
             }
         },
@@ -682,6 +2157,9 @@ const docTemplate = `{
             "properties": {
                 "data": {
                     "$ref": "#/definitions/Role"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
                 }
             }
         },
@@ -693,6 +2171,9 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/Role"
                     }
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
                 }
             }
         },
@@ -713,6 +2194,9 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/PermissionGroup"
                     }
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
@@ -721,6 +2205,9 @@ const docTemplate = `{
             "properties": {
                 "data": {
                     "$ref": "#/definitions/Scope"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
                 }
             }
         },
@@ -732,6 +2219,50 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/Scope"
                     }
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
+                }
+            }
+        },
+        "Token": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "TokenRequest": {
+            "type": "object",
+            "properties": {
+                "cdsid": {
+                    "description": "CDSID selects the user of the token, only privileged clients may request tokens of other users than the subject\nof their bearer token.",
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Scopes default to all dependant scopes of the calling client.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "TokenResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/Token"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
                 }
             }
         },
@@ -741,20 +2272,41 @@ const docTemplate = `{
                 "cdsid": {
                     "type": "string"
                 },
+                "cdsid_unresolved": {
+                    "type": "boolean"
+                },
                 "country_code": {
                     "type": "string"
                 },
                 "email": {
                     "type": "string"
                 },
+                "first_name": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "identities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Identity"
+                    }
+                },
+                "last_name": {
+                    "type": "string"
+                },
                 "partners": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/Partner"
                     }
+                },
+                "unresolved_partners": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -764,6 +2316,13 @@ const docTemplate = `{
                 "context": {
                     "$ref": "#/definitions/Context"
                 },
+                "domains": {
+                    "description": "Domains are the access domains of the data scopes, keyed by scope.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/AccessDomain"
+                    }
+                },
                 "permission_groups": {
                     "type": "object",
                     "additionalProperties": {
@@ -781,11 +2340,52 @@ const docTemplate = `{
                 }
             }
         },
+        "UserAccessBatchRequest": {
+            "type": "object",
+            "properties": {
+                "cdsids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "UserAccessBatchResult": {
+            "type": "object",
+            "properties": {
+                "accesses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UserAccess"
+                    }
+                },
+                "cdsid": {
+                    "type": "string"
+                },
+                "error": {
+                    "$ref": "#/definitions/Error"
+                },
+                "stale": {
+                    "description": "Stale is set when the user data is served from the cache past its TTL.",
+                    "type": "boolean"
+                }
+            }
+        },
         "UserAccessResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "$ref": "#/definitions/UserAccess"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
                 }
             }
         },
@@ -794,9 +2394,20 @@ const docTemplate = `{
             "properties": {
                 "data": {
                     "$ref": "#/definitions/User"
+                },
+                "meta": {
+                    "$ref": "#/definitions/Meta"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Bearer token of the caller, required when AUTH_ENABLED is set",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
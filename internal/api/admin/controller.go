@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/volvo-cars/go-render"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type partnerCache interface {
+	Purge() int
+	PurgePartner(partnerID string) int
+}
+
+type tracer interface {
+	Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span)
+}
+
+type Controller struct {
+	tracer       tracer
+	partnerCache partnerCache
+}
+
+func NewController(partnerCache partnerCache) *Controller {
+	return &Controller{
+		tracer:       otel.Tracer("controller/admin"),
+		partnerCache: partnerCache,
+	}
+}
+
+func (c *Controller) RegisterRoutes(router chi.Router) {
+	router.Route("/cache", func(r chi.Router) {
+		r.Route("/partners", func(r chi.Router) {
+			r.Delete("/", c.purgePartners)
+			r.Delete("/{partnerID}", c.purgePartner)
+		})
+	})
+}
+
+// purgePartners drops every cached cache-manager partner.
+func (c *Controller) purgePartners(w http.ResponseWriter, r *http.Request) {
+	_, span := c.tracer.Start(r.Context(), "controller.purgePartners")
+	defer span.End()
+
+	purged := c.partnerCache.Purge()
+	render.Success(w, http.StatusOK, PurgeResponse{Purged: purged})
+}
+
+// purgePartner drops the cached cache-manager entries of a single partner, by partner ID or PARMA code.
+func (c *Controller) purgePartner(w http.ResponseWriter, r *http.Request) {
+	_, span := c.tracer.Start(r.Context(), "controller.purgePartner")
+	defer span.End()
+
+	partnerID := chi.URLParam(r, "partnerID")
+	if partnerID == "" {
+		c.failure(w, r, http.StatusBadRequest, errors.New("field partner id is invalid"))
+		return
+	}
+
+	purged := c.partnerCache.PurgePartner(partnerID)
+	render.Success(w, http.StatusOK, PurgeResponse{Purged: purged})
+}
+
+func (c *Controller) failure(w http.ResponseWriter, r *http.Request, status int, err error) {
+	span := trace.SpanFromContext(r.Context())
+	span.SetStatus(codes.Error, err.Error())
+	span.RecordError(err)
+
+	render.Failure(w, status, err)
+}
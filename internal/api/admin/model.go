@@ -0,0 +1,5 @@
+package admin
+
+type PurgeResponse struct {
+	Purged int `json:"purged"`
+}
@@ -0,0 +1,152 @@
+package extauthz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/auth"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/extauthz"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/reasons"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Identity headers of allowed requests, list them in allowed_upstream_headers of the ext_authz filter.
+const (
+	SubjectHeader  = "X-Auth-Subject"
+	ClientIDHeader = "X-Auth-Client-Id"
+	ContextsHeader = "X-Auth-Contexts"
+	ScopeHeader    = "X-Auth-Scope"
+)
+
+const (
+	ReasonRouteNotDeclared = "route_not_declared"
+	ReasonNotGranted       = "permission_group_not_granted"
+	ReasonInvalidPath      = "invalid_path"
+)
+
+type authorizer interface {
+	Authorize(ctx context.Context, req extauthz.Request) (extauthz.Result, error)
+}
+
+type denialCollector interface {
+	ObserveDenied(clientID, reason string)
+}
+
+type tracer interface {
+	Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span)
+}
+
+// Controller implements the HTTP service of the Envoy external authorization filter: Envoy forwards the method,
+// path and headers of the original request under the path prefix, a 200 lets the request through with the identity
+// headers and any other status is returned to the caller.
+type Controller struct {
+	tracer     tracer
+	prefix     string
+	authorizer authorizer
+	collector  denialCollector
+}
+
+func NewController(prefix string, authorizer authorizer, collector denialCollector) *Controller {
+	return &Controller{
+		tracer:     otel.Tracer("controller/extauthz"),
+		prefix:     strings.TrimSuffix(prefix, "/"),
+		authorizer: authorizer,
+		collector:  collector,
+	}
+}
+
+// RegisterRoutes registers the handler of any method and path, the router is mounted at the path prefix.
+func (c *Controller) RegisterRoutes(router chi.Router) {
+	router.Handle("/", http.HandlerFunc(c.authorize))
+	router.Handle("/*", http.HandlerFunc(c.authorize))
+}
+
+func (c *Controller) authorize(w http.ResponseWriter, r *http.Request) {
+	ctx, span := c.tracer.Start(r.Context(), "controller.extAuthz")
+	defer span.End()
+
+	path := strings.TrimPrefix(r.URL.EscapedPath(), c.prefix)
+	if path == "" {
+		path = "/"
+	}
+
+	req := extauthz.Request{
+		Host:          r.Host,
+		Method:        r.Method,
+		Path:          path,
+		Authorization: r.Header.Get("Authorization"),
+	}
+	span.SetAttributes(attribute.String("extauthz.method", req.Method), attribute.String("extauthz.path", req.Path))
+
+	result, err := c.authorizer.Authorize(ctx, req)
+	if err != nil {
+		c.deny(ctx, w, result.ClientID, err)
+		return
+	}
+
+	w.Header().Set(SubjectHeader, result.Subject)
+	w.Header().Set(ClientIDHeader, result.ClientID)
+	if result.Route != nil {
+		w.Header().Set(ScopeHeader, result.Route.Scope)
+	}
+	if len(result.Contexts) > 0 {
+		w.Header().Set(ContextsHeader, strings.Join(result.Contexts, ","))
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *Controller) deny(ctx context.Context, w http.ResponseWriter, clientID string, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	status, reason := errorStatus(err)
+	switch {
+	case errors.Is(err, auth.ErrMissingToken):
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	case status == http.StatusUnauthorized:
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	}
+
+	if status == http.StatusForbidden && c.collector != nil {
+		c.collector.ObserveDenied(clientID, reason)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v1.ErrorResponse{
+		Error: v1.Error{Code: status, Message: err.Error(), Reason: reason},
+	})
+}
+
+// errorStatus maps an error of the authorizer to the status and reason returned to the caller.
+func errorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, auth.ErrMissingToken), errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrMissingSubject):
+		return http.StatusUnauthorized, reasons.Unauthenticated
+	case errors.Is(err, extauthz.ErrInvalidPath):
+		return http.StatusBadRequest, ReasonInvalidPath
+	case errors.Is(err, extauthz.ErrRouteNotDeclared):
+		return http.StatusForbidden, ReasonRouteNotDeclared
+	case errors.Is(err, extauthz.ErrNotGranted):
+		return http.StatusForbidden, ReasonNotGranted
+	case errors.Is(err, authz.ErrUserNotFound):
+		return http.StatusForbidden, reasons.UserNotFound
+	case errors.Is(err, gateway.ErrTimeout):
+		return http.StatusGatewayTimeout, reasons.UpstreamTimeout
+	case errors.Is(err, gateway.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable, reasons.UpstreamUnavailable
+	default:
+		return http.StatusInternalServerError, reasons.Internal
+	}
+}
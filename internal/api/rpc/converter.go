@@ -0,0 +1,235 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+
+	pb "github.com/volvo-cars/connect-access-control/internal/api/rpc/accesscontrolv1"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+var (
+	errInvalidUser   = errors.New("field user is invalid, one of cdsid, email, user_id or identity is required")
+	errInvalidTarget = errors.New("field target is invalid, one of partner, distributor or market is required")
+	errInvalidScopes = errors.New("field scopes is invalid")
+)
+
+func fromUserLookup(user *pb.UserLookup) (plums.Lookup, error) {
+	lookup, err := userLookup(user)
+	if err != nil {
+		return plums.Lookup{}, err
+	}
+
+	if !lookup.Valid() {
+		return plums.Lookup{}, fmt.Errorf("%w: values must not contain /, \\ or ..", plums.ErrInvalidLookup)
+	}
+
+	return lookup, nil
+}
+
+func userLookup(user *pb.UserLookup) (plums.Lookup, error) {
+	switch kind := user.GetKind().(type) {
+	case *pb.UserLookup_Cdsid:
+		if kind.Cdsid != "" {
+			return plums.ByCDSID(kind.Cdsid), nil
+		}
+	case *pb.UserLookup_Email:
+		if kind.Email != "" {
+			return plums.ByEmail(kind.Email), nil
+		}
+	case *pb.UserLookup_UserId:
+		if kind.UserId != "" {
+			return plums.ByUserID(kind.UserId), nil
+		}
+	case *pb.UserLookup_Identity:
+		if kind.Identity.GetProvider() != "" && kind.Identity.GetProviderUserId() != "" {
+			return plums.ByIdentity(kind.Identity.GetProvider(), kind.Identity.GetProviderUserId()), nil
+		}
+	}
+
+	return plums.Lookup{}, errInvalidUser
+}
+
+func fromTarget(target *pb.Target) (authz.Target, error) {
+	switch kind := target.GetKind().(type) {
+	case *pb.Target_Partner:
+		if kind.Partner != "" {
+			return authz.Target{Domain: store.DomainPartner, ID: kind.Partner, PartnerType: target.GetPartnerType()}, nil
+		}
+	case *pb.Target_Distributor:
+		if kind.Distributor != "" {
+			return authz.Target{Domain: store.DomainDistributor, ID: kind.Distributor}, nil
+		}
+	case *pb.Target_Market:
+		if kind.Market != "" {
+			return authz.Target{Domain: store.DomainMarket, ID: kind.Market}, nil
+		}
+	}
+
+	return authz.Target{}, errInvalidTarget
+}
+
+func accessOptions(request *pb.GetUserAccessRequest) []authz.AccessOption {
+	var opts []authz.AccessOption
+	if request.GetContext() != "" {
+		opts = append(opts, authz.WithContext(request.GetContext()))
+	}
+
+	if request.GetPrimary() {
+		opts = append(opts, authz.WithPrimaryContext())
+	}
+
+	return opts
+}
+
+func toUserAccesses(accesses []authz.UserAccess) []*pb.UserAccess {
+	arr := make([]*pb.UserAccess, len(accesses))
+	for i, access := range accesses {
+		permissionGroups := make(map[string]*pb.PermissionGroups, len(access.PermissionGroups))
+		for scope, keys := range access.PermissionGroups {
+			permissionGroups[scope] = &pb.PermissionGroups{Keys: keys}
+		}
+
+		domains := make(map[string]*pb.AccessDomain, len(access.Domains))
+		for scope, domain := range access.Domains {
+			domains[scope] = &pb.AccessDomain{
+				Partners:     domain.Partners,
+				Distributors: domain.Distributors,
+				Markets:      domain.Markets,
+			}
+		}
+
+		arr[i] = &pb.UserAccess{
+			Context:          toContext(access.Context),
+			Roles:            access.Roles,
+			PermissionGroups: permissionGroups,
+			Domains:          domains,
+		}
+	}
+
+	return arr
+}
+
+func toContext(context authz.Context) *pb.Context {
+	return &pb.Context{
+		Id:            context.ID,
+		Type:          context.Type,
+		Tag:           context.Tag,
+		Inherited:     context.Inherited,
+		InheritedFrom: context.InheritedFrom,
+	}
+}
+
+func toDecision(decision authz.Decision) *pb.CheckResponse {
+	response := &pb.CheckResponse{
+		Allowed: decision.Allowed,
+		Reason:  decision.Reason.String(),
+	}
+
+	if decision.Context != nil {
+		response.Context = toContext(*decision.Context)
+	}
+
+	return response
+}
+
+func toClient(client store.Client) *pb.Client {
+	return &pb.Client{
+		Id:                 client.ID,
+		Name:               client.Name,
+		Description:        client.Description,
+		WhitelistedDomains: client.WhitelistedDomains,
+		DependantScopes:    client.DependantScopes,
+	}
+}
+
+func toClients(clients []store.Client) []*pb.Client {
+	arr := make([]*pb.Client, len(clients))
+	for i, client := range clients {
+		arr[i] = toClient(client)
+	}
+
+	return arr
+}
+
+func toRole(role store.Role) *pb.Role {
+	return &pb.Role{
+		Id:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+	}
+}
+
+func toRoles(roles []store.Role) []*pb.Role {
+	arr := make([]*pb.Role, len(roles))
+	for i, role := range roles {
+		arr[i] = toRole(role)
+	}
+
+	return arr
+}
+
+func toScope(scope store.Scope) *pb.Scope {
+	permissionGroups := make([]*pb.PermissionGroup, len(scope.PermissionGroups))
+	for i, group := range scope.PermissionGroups {
+		permissionGroups[i] = &pb.PermissionGroup{
+			Key:         group.Key,
+			Label:       group.Label,
+			Description: group.Description,
+		}
+	}
+
+	return &pb.Scope{
+		Key:              scope.Key,
+		Label:            scope.Label,
+		Description:      scope.Description,
+		Type:             scope.Type.String(),
+		PermissionGroups: permissionGroups,
+	}
+}
+
+func toScopes(scopes []store.Scope) []*pb.Scope {
+	arr := make([]*pb.Scope, len(scopes))
+	for i, scope := range scopes {
+		arr[i] = toScope(scope)
+	}
+
+	return arr
+}
+
+func toRoleMapping(mapping store.RoleMapping) *pb.RoleMapping {
+	arr := make([]*pb.Mapping, len(mapping.Mapping))
+	for i, m := range mapping.Mapping {
+		domains := make([]string, len(m.Domains))
+		for j, domain := range m.Domains {
+			domains[j] = domain.String()
+		}
+
+		arr[i] = &pb.Mapping{
+			Filter: &pb.Filter{
+				Market:      m.Filter.Market,
+				UserType:    m.Filter.UserType,
+				PartnerType: m.Filter.PartnerType,
+			},
+			PermissionGroups: m.PermissionGroups,
+			Domains:          domains,
+			Inherit:          m.Inherit,
+		}
+	}
+
+	return &pb.RoleMapping{
+		RoleId:  mapping.RoleID,
+		Mapping: arr,
+	}
+}
+
+func toRoleMappings(mappings []store.RoleMapping) []*pb.RoleMapping {
+	arr := make([]*pb.RoleMapping, len(mappings))
+	for i, mapping := range mappings {
+		arr[i] = toRoleMapping(mapping)
+	}
+
+	return arr
+}
@@ -0,0 +1,78 @@
+package rpc
+
+import (
+	"errors"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/auth"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/reasons"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo details of the errors of the service.
+const errorDomain = "connect-access-control"
+
+// errorCode maps an error of the authz service or the store to the gRPC code and the reason of the REST API.
+func errorCode(err error) (codes.Code, string) {
+	switch {
+	case errors.Is(err, errInvalidUser), errors.Is(err, errInvalidScopes):
+		return codes.InvalidArgument, ""
+	case errors.Is(err, plums.ErrInvalidLookup):
+		return codes.InvalidArgument, reasons.InvalidUser
+	case errors.Is(err, errInvalidTarget), errors.Is(err, authz.ErrInvalidTarget):
+		return codes.InvalidArgument, reasons.InvalidTarget
+	case errors.Is(err, authz.ErrInvalidCheck):
+		return codes.InvalidArgument, reasons.InvalidCheck
+	case errors.Is(err, authz.ErrUserNotFound):
+		return codes.NotFound, reasons.UserNotFound
+	case errors.Is(err, authz.ErrContextNotFound):
+		return codes.NotFound, reasons.ContextNotFound
+	case errors.Is(err, authz.ErrClientNotFound), errors.Is(err, store.ErrClientNotFound):
+		return codes.NotFound, reasons.ClientNotFound
+	case errors.Is(err, authz.ErrTargetNotFound):
+		return codes.NotFound, reasons.TargetNotFound
+	case errors.Is(err, store.ErrRoleNotFound), errors.Is(err, store.ErrScopeNotFound), errors.Is(err, store.ErrRoleMappingNotFound):
+		return codes.NotFound, ""
+	case errors.Is(err, authz.ErrScopeNotAllowed):
+		return codes.PermissionDenied, reasons.ScopeNotAllowed
+	case errors.Is(err, auth.ErrNotAuthenticated), errors.Is(err, auth.ErrMissingToken), errors.Is(err, auth.ErrInvalidToken),
+		errors.Is(err, auth.ErrMissingSubject), errors.Is(err, auth.ErrMissingClient):
+		return codes.Unauthenticated, reasons.Unauthenticated
+	case errors.Is(err, auth.ErrNotPrivileged):
+		return codes.PermissionDenied, reasons.ClientNotPrivileged
+	case errors.Is(err, auth.ErrUnknownClient):
+		return codes.PermissionDenied, reasons.ClientNotRegistered
+	case errors.Is(err, gateway.ErrNotFound):
+		return codes.NotFound, reasons.UpstreamNotFound
+	case errors.Is(err, gateway.ErrTimeout):
+		return codes.DeadlineExceeded, reasons.UpstreamTimeout
+	case errors.Is(err, gateway.ErrUpstreamUnavailable):
+		return codes.Unavailable, reasons.UpstreamUnavailable
+	case errors.Is(err, gateway.ErrUnauthorized):
+		return codes.Unavailable, reasons.UpstreamUnauthorized
+	case errors.Is(err, gateway.ErrMalformedResponse):
+		return codes.Internal, reasons.UpstreamMalformedResponse
+	default:
+		return codes.Internal, reasons.Internal
+	}
+}
+
+// toStatus returns the status error of err, with the reason as ErrorInfo detail.
+func toStatus(err error) error {
+	code, reason := errorCode(err)
+	st := status.New(code, err.Error())
+	if reason == "" {
+		return st.Err()
+	}
+
+	if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}); detailErr == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
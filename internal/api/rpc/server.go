@@ -0,0 +1,422 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	pb "github.com/volvo-cars/connect-access-control/internal/api/rpc/accesscontrolv1"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/auth"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/reasons"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// healthService is not authenticated, so that probes and load balancers need no credentials.
+const healthService = "/grpc.health.v1.Health/"
+
+const (
+	defaultBatchConcurrency = 8
+	defaultBatchMaxChecks   = 1000
+)
+
+type authzClient interface {
+	GetUserAccess(ctx context.Context, lookup plums.Lookup, scopes []string, opts ...authz.AccessOption) (authz.Access, error)
+	GetClientUserAccess(ctx context.Context, clientID string, lookup plums.Lookup, scopes []string, opts ...authz.AccessOption) (authz.ClientAccess, error)
+	Check(ctx context.Context, lookup plums.Lookup, scope, permissionGroup string, target authz.Target) (authz.Decision, error)
+	BatchCheck(ctx context.Context, checks []authz.CheckRequest, concurrency int) []authz.CheckResult
+}
+
+type authzStore interface {
+	GetClient(key string) (store.Client, error)
+	GetClients() ([]store.Client, error)
+	GetRole(id string) (store.Role, error)
+	GetRoles() ([]store.Role, error)
+	GetScope(key string) (store.Scope, error)
+	GetScopes() ([]store.Scope, error)
+	GetRoleMapping(scopeID, roleID string) (store.RoleMapping, error)
+	GetRoleMappings(scopeID string) ([]store.RoleMapping, error)
+}
+
+type authenticator interface {
+	Authenticate(ctx context.Context, token string) (auth.Principal, error)
+	Required() bool
+	Privileged(principal auth.Principal) bool
+	RestrictUserRoutes() bool
+	EnforceClientScopes() bool
+	ObserveDenied(clientID, reason string)
+}
+
+// Server serves the AccessControlService with the same authz service and store as the REST API.
+type Server struct {
+	pb.UnimplementedAccessControlServiceServer
+
+	authzStore    authzStore
+	authzClient   authzClient
+	authenticator authenticator
+
+	batchConcurrency int
+	batchMaxChecks   int
+}
+
+type ServerOption func(*Server)
+
+// WithAuthenticator authenticates the bearer token of the authorization metadata, and applies the privileged client
+// and client scope restrictions of the REST API.
+func WithAuthenticator(authenticator authenticator) ServerOption {
+	return func(s *Server) {
+		s.authenticator = authenticator
+	}
+}
+
+// WithBatchLimits bounds the checks of a batch decided at once and the checks a batch may hold, unset limits keep
+// their defaults.
+func WithBatchLimits(concurrency, maxChecks int) ServerOption {
+	return func(s *Server) {
+		if concurrency > 0 {
+			s.batchConcurrency = concurrency
+		}
+		if maxChecks > 0 {
+			s.batchMaxChecks = maxChecks
+		}
+	}
+}
+
+func NewServer(svc authzStore, authzClient authzClient, opts ...ServerOption) *Server {
+	s := &Server{
+		authzStore:       svc,
+		authzClient:      authzClient,
+		batchConcurrency: defaultBatchConcurrency,
+		batchMaxChecks:   defaultBatchMaxChecks,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	pb.RegisterAccessControlServiceServer(registrar, s)
+}
+
+// UnaryInterceptor authenticates the bearer token of a call and adds the principal to its context. Calls without a
+// token are only passed on anonymously when authentication is not required.
+func (s *Server) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if s.authenticator == nil || strings.HasPrefix(info.FullMethod, healthService) {
+		return handler(ctx, req)
+	}
+
+	token, ok := bearerToken(ctx)
+	if !ok {
+		if s.authenticator.Required() {
+			s.authenticator.ObserveDenied("", reasons.Unauthenticated)
+			return nil, status.Error(codes.Unauthenticated, auth.ErrMissingToken.Error())
+		}
+		return handler(ctx, req)
+	}
+
+	principal, err := s.authenticator.Authenticate(ctx, token)
+	if err != nil {
+		s.authenticator.ObserveDenied("", reasons.Unauthenticated)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return handler(auth.NewContext(ctx, principal), req)
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	for _, value := range md.Get("authorization") {
+		scheme, token, ok := strings.Cut(value, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") && token != "" {
+			return strings.TrimSpace(token), true
+		}
+	}
+
+	return "", false
+}
+
+func (s *Server) GetUserAccess(ctx context.Context, request *pb.GetUserAccessRequest) (*pb.GetUserAccessResponse, error) {
+	lookup, err := fromUserLookup(request.GetUser())
+	if err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	scopes := request.GetScopes()
+	if request.GetClientId() != "" {
+		if err := s.authorizeClientScopes(ctx, request.GetClientId(), scopes); err != nil {
+			return nil, s.failure(ctx, err)
+		}
+
+		access, err := s.authzClient.GetClientUserAccess(ctx, request.GetClientId(), lookup, scopes, accessOptions(request)...)
+		if err != nil {
+			return nil, s.failure(ctx, err)
+		}
+
+		return &pb.GetUserAccessResponse{Accesses: toUserAccesses(access.Accesses), Stale: access.User.Stale}, nil
+	}
+
+	// as in the REST API, only the access of a client defaults its scopes
+	if len(scopes) == 0 {
+		return nil, s.failure(ctx, errInvalidScopes)
+	}
+
+	if err := s.authorizeScopes(ctx, scopes); err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	access, err := s.authzClient.GetUserAccess(ctx, lookup, scopes, accessOptions(request)...)
+	if err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	return &pb.GetUserAccessResponse{Accesses: toUserAccesses(access.Accesses), Stale: access.User.Stale}, nil
+}
+
+func (s *Server) Check(ctx context.Context, request *pb.CheckRequest) (*pb.CheckResponse, error) {
+	decision, err := s.check(ctx, request)
+	if err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	return decision, nil
+}
+
+// BatchCheck decides the checks with the bounded concurrency of the batch access endpoint, reusing the access of a
+// user for all of its checks of a scope.
+func (s *Server) BatchCheck(ctx context.Context, request *pb.BatchCheckRequest) (*pb.BatchCheckResponse, error) {
+	if len(request.GetChecks()) > s.batchMaxChecks {
+		return nil, status.Errorf(codes.InvalidArgument, "a batch holds at most %d checks", s.batchMaxChecks)
+	}
+
+	results := make([]*pb.CheckResult, len(request.GetChecks()))
+
+	// checks that are invalid or not allowed fail on their own, the others are decided together
+	var (
+		checks  []authz.CheckRequest
+		indexes []int
+	)
+	for i, check := range request.GetChecks() {
+		checkRequest, err := s.checkRequest(ctx, check)
+		if err != nil {
+			results[i] = s.checkError(ctx, err)
+			continue
+		}
+
+		checks = append(checks, checkRequest)
+		indexes = append(indexes, i)
+	}
+
+	for i, result := range s.authzClient.BatchCheck(ctx, checks, s.batchConcurrency) {
+		if result.Err != nil {
+			results[indexes[i]] = s.checkError(ctx, result.Err)
+			continue
+		}
+
+		results[indexes[i]] = &pb.CheckResult{Result: &pb.CheckResult_Decision{Decision: toDecision(result.Decision)}}
+	}
+
+	return &pb.BatchCheckResponse{Results: results}, nil
+}
+
+// checkError is the result of a failed check of a batch.
+func (s *Server) checkError(ctx context.Context, err error) *pb.CheckResult {
+	code, reason := errorCode(err)
+	s.observeDenied(ctx, code, reason)
+
+	return &pb.CheckResult{Result: &pb.CheckResult_Error{Error: &pb.Error{
+		Code:    int32(code),
+		Reason:  reason,
+		Message: err.Error(),
+	}}}
+}
+
+func (s *Server) check(ctx context.Context, request *pb.CheckRequest) (*pb.CheckResponse, error) {
+	check, err := s.checkRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := s.authzClient.Check(ctx, check.Lookup, check.Scope, check.PermissionGroup, check.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDecision(decision), nil
+}
+
+// checkRequest validates a check and authorizes its scope for the caller.
+func (s *Server) checkRequest(ctx context.Context, request *pb.CheckRequest) (authz.CheckRequest, error) {
+	lookup, err := fromUserLookup(request.GetUser())
+	if err != nil {
+		return authz.CheckRequest{}, err
+	}
+
+	target, err := fromTarget(request.GetTarget())
+	if err != nil {
+		return authz.CheckRequest{}, err
+	}
+
+	if err := s.authorizeScopes(ctx, []string{request.GetScope()}); err != nil {
+		return authz.CheckRequest{}, err
+	}
+
+	return authz.CheckRequest{
+		Lookup:          lookup,
+		Scope:           request.GetScope(),
+		PermissionGroup: request.GetPermissionGroup(),
+		Target:          target,
+	}, nil
+}
+
+func (s *Server) ListClients(ctx context.Context, _ *pb.ListClientsRequest) (*pb.ListClientsResponse, error) {
+	clients, err := s.authzStore.GetClients()
+	if err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	return &pb.ListClientsResponse{Clients: toClients(clients)}, nil
+}
+
+func (s *Server) GetClient(ctx context.Context, request *pb.GetClientRequest) (*pb.Client, error) {
+	client, err := s.authzStore.GetClient(request.GetId())
+	if err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	return toClient(client), nil
+}
+
+func (s *Server) ListRoles(ctx context.Context, _ *pb.ListRolesRequest) (*pb.ListRolesResponse, error) {
+	roles, err := s.authzStore.GetRoles()
+	if err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	return &pb.ListRolesResponse{Roles: toRoles(roles)}, nil
+}
+
+func (s *Server) GetRole(ctx context.Context, request *pb.GetRoleRequest) (*pb.Role, error) {
+	role, err := s.authzStore.GetRole(request.GetId())
+	if err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	return toRole(role), nil
+}
+
+func (s *Server) ListScopes(ctx context.Context, _ *pb.ListScopesRequest) (*pb.ListScopesResponse, error) {
+	scopes, err := s.authzStore.GetScopes()
+	if err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	return &pb.ListScopesResponse{Scopes: toScopes(scopes)}, nil
+}
+
+func (s *Server) GetScope(ctx context.Context, request *pb.GetScopeRequest) (*pb.Scope, error) {
+	scope, err := s.authzStore.GetScope(request.GetKey())
+	if err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	return toScope(scope), nil
+}
+
+func (s *Server) ListRoleMappings(ctx context.Context, request *pb.ListRoleMappingsRequest) (*pb.ListRoleMappingsResponse, error) {
+	if _, err := s.authzStore.GetScope(request.GetScope()); err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	mappings, err := s.authzStore.GetRoleMappings(request.GetScope())
+	if err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	return &pb.ListRoleMappingsResponse{Mappings: toRoleMappings(mappings)}, nil
+}
+
+func (s *Server) GetRoleMapping(ctx context.Context, request *pb.GetRoleMappingRequest) (*pb.RoleMapping, error) {
+	mapping, err := s.authzStore.GetRoleMapping(request.GetScope(), request.GetRoleId())
+	if err != nil {
+		return nil, s.failure(ctx, err)
+	}
+
+	return toRoleMapping(mapping), nil
+}
+
+// authorizeScopes applies the privileged client restriction and limits an authenticated caller to the dependant scopes
+// of its client, as the user endpoints of the REST API do.
+func (s *Server) authorizeScopes(ctx context.Context, scopes []string) error {
+	if s.authenticator == nil {
+		return nil
+	}
+
+	principal, ok := auth.FromContext(ctx)
+	if s.authenticator.RestrictUserRoutes() {
+		if !ok {
+			return auth.ErrNotAuthenticated
+		}
+
+		if !s.authenticator.Privileged(principal) {
+			return fmt.Errorf("%w: [%s]", auth.ErrNotPrivileged, principal.ClientID)
+		}
+	}
+
+	if !ok || !s.authenticator.EnforceClientScopes() {
+		return nil
+	}
+
+	caller, err := s.authzStore.GetClient(principal.ClientID)
+	if err != nil {
+		return fmt.Errorf("%w: [%s]", auth.ErrUnknownClient, principal.ClientID)
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(caller.DependantScopes, scope) {
+			return fmt.Errorf("%w: [%s] is not a dependant scope of the calling client [%s]", authz.ErrScopeNotAllowed, scope, caller.ID)
+		}
+	}
+
+	return nil
+}
+
+// authorizeClientScopes authorizes the scopes of a client access request, which default to the dependant scopes of
+// the requested client.
+func (s *Server) authorizeClientScopes(ctx context.Context, clientID string, scopes []string) error {
+	if len(scopes) == 0 {
+		if client, err := s.authzStore.GetClient(clientID); err == nil {
+			scopes = client.DependantScopes
+		}
+	}
+
+	return s.authorizeScopes(ctx, scopes)
+}
+
+// failure returns the status error of err and counts denied calls.
+func (s *Server) failure(ctx context.Context, err error) error {
+	code, reason := errorCode(err)
+	s.observeDenied(ctx, code, reason)
+	return toStatus(err)
+}
+
+func (s *Server) observeDenied(ctx context.Context, code codes.Code, reason string) {
+	if code != codes.PermissionDenied || s.authenticator == nil {
+		return
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	s.authenticator.ObserveDenied(principal.ClientID, reason)
+}
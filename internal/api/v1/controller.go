@@ -2,21 +2,33 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/auth"
 	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
 	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/token"
 	"github.com/volvo-cars/go-render"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type authzClient interface {
-	GetUserByCDSID(ctx context.Context, cdsid string) (authz.User, error)
-	GetUserAccess(ctx context.Context, cdsid string, scopes []string) ([]authz.UserAccess, error)
+	GetUser(ctx context.Context, lookup plums.Lookup) (authz.User, error)
+	GetUserAccess(ctx context.Context, lookup plums.Lookup, scopes []string, opts ...authz.AccessOption) (authz.Access, error)
+	GetClientUserAccess(ctx context.Context, clientID string, lookup plums.Lookup, scopes []string, opts ...authz.AccessOption) (authz.ClientAccess, error)
+	Check(ctx context.Context, lookup plums.Lookup, scope, permissionGroup string, target authz.Target) (authz.Decision, error)
+	GetUsersAccess(ctx context.Context, lookups []plums.Lookup, scopes []string, concurrency int, yield func(authz.BatchResult))
 }
 
 type authzStore interface {
@@ -28,6 +40,20 @@ type authzStore interface {
 	GetScopes() ([]store.Scope, error)
 	GetRoleMapping(scopeID, roleID string) (store.RoleMapping, error)
 	GetRoleMappings(scopeID string) ([]store.RoleMapping, error)
+	Digest() string
+}
+
+type authenticator interface {
+	Lookup(principal auth.Principal) (plums.Lookup, error)
+	Owns(principal auth.Principal, lookup plums.Lookup) bool
+	Privileged(principal auth.Principal) bool
+	RestrictUserRoutes() bool
+	EnforceClientScopes() bool
+	ObserveDenied(clientID, reason string)
+}
+
+type tokenSigner interface {
+	Sign(subject, audience string, claims map[string]any) (token.Token, error)
 }
 
 type tracer interface {
@@ -35,26 +61,100 @@ type tracer interface {
 }
 
 type Controller struct {
-	tracer      tracer
-	authzStore  authzStore
-	authzClient authzClient
+	tracer        tracer
+	authzStore    authzStore
+	authzClient   authzClient
+	authenticator authenticator
+	tokenSigner   tokenSigner
+
+	batchConcurrency int
+	batchMaxUsers    int
+
+	events            eventBroker
+	heartbeatInterval time.Duration
+}
+
+type ControllerOption func(*Controller)
+
+// WithAuthenticator serves the me endpoints for the subject of the bearer token, and restricts the user endpoints to
+// privileged clients when the authenticator is configured to.
+func WithAuthenticator(authenticator authenticator) ControllerOption {
+	return func(c *Controller) {
+		c.authenticator = authenticator
+	}
+}
+
+// WithTokenSigner serves POST /iam/token, which mints tokens carrying the access of the user for the scopes of the
+// calling client. It requires an authenticator.
+func WithTokenSigner(signer tokenSigner) ControllerOption {
+	return func(c *Controller) {
+		c.tokenSigner = signer
+	}
+}
+
+// WithBatchLimits bounds the users of a batch access request evaluated at once and the users a batch may hold, unset
+// limits keep their defaults.
+func WithBatchLimits(concurrency, maxUsers int) ControllerOption {
+	return func(c *Controller) {
+		if concurrency > 0 {
+			c.batchConcurrency = concurrency
+		}
+		if maxUsers > 0 {
+			c.batchMaxUsers = maxUsers
+		}
+	}
 }
 
-func NewController(svc authzStore, authzClient authzClient) *Controller {
-	return &Controller{
-		tracer:      otel.Tracer("controller/iam"),
-		authzStore:  svc,
-		authzClient: authzClient,
+// WithEvents serves GET /iam/events, the stream of the changes of the IAM configuration. Idle streams get a comment
+// every heartbeatInterval.
+func WithEvents(broker eventBroker, heartbeatInterval time.Duration) ControllerOption {
+	return func(c *Controller) {
+		c.events = broker
+		if heartbeatInterval > 0 {
+			c.heartbeatInterval = heartbeatInterval
+		}
 	}
 }
 
+func NewController(svc authzStore, authzClient authzClient, opts ...ControllerOption) *Controller {
+	c := &Controller{
+		tracer:           otel.Tracer("controller/iam"),
+		authzStore:       svc,
+		authzClient:      authzClient,
+		batchConcurrency: defaultBatchConcurrency,
+		batchMaxUsers:    defaultBatchMaxUsers,
+
+		heartbeatInterval: defaultHeartbeatInterval,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
 func (c *Controller) RegisterRoutes(router chi.Router) {
 	router.Route("/iam", func(r chi.Router) {
 		r.Route("/clients", func(r chi.Router) {
 			r.Get("/", c.getClients)
 			r.Get("/{clientID}", c.getClient)
+			r.With(c.privileged).Get("/{clientID}/users/{cdsid}/access", c.getClientUserAccess)
+		})
+
+		if c.events != nil {
+			r.Get("/events", c.getEvents)
+		}
+
+		r.Route("/me", func(r chi.Router) {
+			r.Get("/", c.getMe)
+			r.Get("/access", c.getMeAccess)
 		})
 
+		if c.tokenSigner != nil {
+			r.Post("/token", c.postToken)
+		}
+
 		r.Route("/roles", func(r chi.Router) {
 			r.Get("/", c.getRoles)
 			r.Get("/{roleID}", c.getRole)
@@ -68,8 +168,14 @@ func (c *Controller) RegisterRoutes(router chi.Router) {
 		})
 
 		r.Route("/users", func(r chi.Router) {
+			r.Use(c.privileged)
+			r.Get("/", c.findUser)
+			r.Get("/access", c.findUserAccess)
+			r.Post("/access:batch", c.postUsersAccessBatch)
+			r.Get("/check", c.findUserCheck)
 			r.Get("/{cdsid}", c.getUser)
 			r.Get("/{cdsid}/access", c.getUserAccess)
+			r.Get("/{cdsid}/check", c.getUserCheck)
 		})
 	})
 }
@@ -83,6 +189,7 @@ func (c *Controller) RegisterRoutes(router chi.Router) {
 //	@Produce		json
 //	@Param			id	path		string	true	"Client ID"
 //	@Success		200	{object}	ClientResponse
+//	@Header			200	{string}	ETag	"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
 //	@Failure		400	{object}	ErrorResponse
 //	@Failure		404	{object}	ErrorResponse
 //	@Failure		500	{object}	ErrorResponse
@@ -108,19 +215,95 @@ func (c *Controller) getClient(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if c.catalogNotModified(w, r) {
+		return
+	}
+
 	response := toClient(client)
 	render.Success(w, http.StatusOK, response)
 }
 
+// GetClientUserAccess godoc
+//
+//	@Summary		get client user access
+//	@Description	get the access of a user for the dependant scopes of a client, together with the client
+//	@Tags			clients
+//	@Accept			json
+//	@Produce		json
+//	@Param			clientID	path		string		true	"Client ID"
+//	@Param			cdsid		path		string		true	"User CDSID"
+//	@Param			scope		query		[]string	false	"Scope key, defaults to all dependant scopes of the client"
+//	@Param			context		query		string		false	"Evaluate only the partner context with this PLUMS or cache-manager partner ID"
+//	@Param			primary		query		bool		false	"Evaluate only the primary partner context"
+//	@Success		200			{object}	ClientUserAccessResponse
+//	@Header			200			{string}	X-Data-Stale	"set to true when the user data is served from a stale cache entry"
+//	@Header			200			{string}	ETag			"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Failure		502			{object}	ErrorResponse
+//	@Failure		503			{object}	ErrorResponse
+//	@Failure		504			{object}	ErrorResponse
+//	@Router			/iam/clients/{clientID}/users/{cdsid}/access [get]
+func (c *Controller) getClientUserAccess(w http.ResponseWriter, r *http.Request) {
+	ctx, span := c.tracer.Start(r.Context(), "controller.getClientUserAccess")
+	defer span.End()
+
+	clientID := chi.URLParam(r, "clientID")
+	if clientID == "" {
+		c.failure(w, r, http.StatusBadRequest, errors.New("field client id is invalid"))
+		return
+	}
+
+	cdsid := chi.URLParam(r, "cdsid")
+	if cdsid == "" {
+		c.failure(w, r, http.StatusBadRequest, errors.New("field user id is invalid"))
+		return
+	}
+
+	opts, err := accessOptions(r.URL.Query())
+	if err != nil {
+		c.failure(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	scopes := r.URL.Query()["scope"]
+	if err := c.authorizeClientScopes(ctx, clientID, scopes); err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	access, err := c.authzClient.GetClientUserAccess(ctx, clientID, plums.ByCDSID(cdsid), scopes, opts...)
+	if err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	meta := userMeta(w, access.User)
+	if c.accessNotModified(w, r, access.Access) {
+		return
+	}
+
+	response := toClientUserAccess(access)
+	success(w, http.StatusOK, response, meta)
+}
+
 // GetClients godoc
 //
 //	@Summary		get clients
-//	@Description	get all clients
+//	@Description	get all clients, sorted and paged
 //	@Tags			clients
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	ClientsResponse
-//	@Failure		500	{object}	ErrorResponse
+//	@Param			limit			query		int		false	"Page size, at most 1000, the whole list is returned without a limit"
+//	@Param			offset			query		int		false	"Number of items to skip"
+//	@Param			sort			query		string	false	"Sort field, one of id or name, prefixed with - for a descending order"	default(id)
+//	@Param			dependant_scope	query		string	false	"Only clients depending on the scope"
+//	@Success		200				{object}	ClientsResponse
+//	@Header			200				{string}	ETag	"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
 //	@Router			/iam/clients [get]
 func (c *Controller) getClients(w http.ResponseWriter, r *http.Request) {
 	_, span := c.tracer.Start(r.Context(), "controller.getClients")
@@ -132,8 +315,18 @@ func (c *Controller) getClients(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := toClients(clients)
-	render.Success(w, http.StatusOK, response)
+	query, err := listFromQuery(r.URL.Query(), clientSortKeys, "id")
+	if err != nil {
+		c.failure(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if c.catalogNotModified(w, r) {
+		return
+	}
+
+	response, page := paginate(filterClients(toClients(clients), r.URL.Query()), query)
+	success(w, http.StatusOK, response, Meta{Page: page})
 }
 
 // GetRole godoc
@@ -145,6 +338,7 @@ func (c *Controller) getClients(w http.ResponseWriter, r *http.Request) {
 //	@Produce		json
 //	@Param			id	path		string	true	"Role ID"
 //	@Success		200	{object}	RoleResponse
+//	@Header			200	{string}	ETag	"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
 //	@Failure		400	{object}	ErrorResponse
 //	@Failure		404	{object}	ErrorResponse
 //	@Failure		500	{object}	ErrorResponse
@@ -170,6 +364,10 @@ func (c *Controller) getRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if c.catalogNotModified(w, r) {
+		return
+	}
+
 	response := toRole(role)
 	render.Success(w, http.StatusOK, response)
 }
@@ -177,12 +375,18 @@ func (c *Controller) getRole(w http.ResponseWriter, r *http.Request) {
 // GetRoles godoc
 //
 //	@Summary		get roles
-//	@Description	get all roles
+//	@Description	get all roles, sorted and paged
 //	@Tags			roles
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	RolesResponse
-//	@Failure		500	{object}	ErrorResponse
+//	@Param			limit	query		int		false	"Page size, at most 1000, the whole list is returned without a limit"
+//	@Param			offset	query		int		false	"Number of items to skip"
+//	@Param			sort	query		string	false	"Sort field, one of id or name, prefixed with - for a descending order"	default(id)
+//	@Param			name	query		string	false	"Only roles whose name contains the text, ignoring case"
+//	@Success		200		{object}	RolesResponse
+//	@Header			200		{string}	ETag	"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
 //	@Router			/iam/roles [get]
 func (c *Controller) getRoles(w http.ResponseWriter, r *http.Request) {
 	_, span := c.tracer.Start(r.Context(), "controller.getRoles")
@@ -194,8 +398,18 @@ func (c *Controller) getRoles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := toRoles(roles)
-	render.Success(w, http.StatusOK, response)
+	query, err := listFromQuery(r.URL.Query(), roleSortKeys, "id")
+	if err != nil {
+		c.failure(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if c.catalogNotModified(w, r) {
+		return
+	}
+
+	response, page := paginate(filterRoles(toRoles(roles), r.URL.Query()), query)
+	success(w, http.StatusOK, response, Meta{Page: page})
 }
 
 // GetScope godoc
@@ -207,6 +421,7 @@ func (c *Controller) getRoles(w http.ResponseWriter, r *http.Request) {
 //	@Produce		json
 //	@Param			scopeKey	path		string	true	"Scope key"
 //	@Success		200			{object}	ScopeResponse
+//	@Header			200			{string}	ETag	"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
 //	@Failure		400			{object}	ErrorResponse
 //	@Failure		404			{object}	ErrorResponse
 //	@Failure		500			{object}	ErrorResponse
@@ -232,6 +447,10 @@ func (c *Controller) getScope(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if c.catalogNotModified(w, r) {
+		return
+	}
+
 	response := toScope(scopes)
 	render.Success(w, http.StatusOK, response)
 }
@@ -239,12 +458,18 @@ func (c *Controller) getScope(w http.ResponseWriter, r *http.Request) {
 // GetScopes godoc
 //
 //	@Summary		get scopes
-//	@Description	get all scopes
+//	@Description	get all scopes, sorted and paged
 //	@Tags			scopes
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	ScopesResponse
-//	@Failure		500	{object}	ErrorResponse
+//	@Param			limit	query		int		false	"Page size, at most 1000, the whole list is returned without a limit"
+//	@Param			offset	query		int		false	"Number of items to skip"
+//	@Param			sort	query		string	false	"Sort field, one of key, label or type, prefixed with - for a descending order"	default(key)
+//	@Param			type	query		string	false	"Only scopes of the type, functionality or data"
+//	@Success		200		{object}	ScopesResponse
+//	@Header			200		{string}	ETag	"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
 //	@Router			/iam/scopes [get]
 func (c *Controller) getScopes(w http.ResponseWriter, r *http.Request) {
 	_, span := c.tracer.Start(r.Context(), "controller.getScopes")
@@ -256,8 +481,18 @@ func (c *Controller) getScopes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := toScopes(scopes)
-	render.Success(w, http.StatusOK, response)
+	query, err := listFromQuery(r.URL.Query(), scopeSortKeys, "key")
+	if err != nil {
+		c.failure(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if c.catalogNotModified(w, r) {
+		return
+	}
+
+	response, page := paginate(filterScopes(toScopes(scopes), r.URL.Query()), query)
+	success(w, http.StatusOK, response, Meta{Page: page})
 }
 
 // GetRoleMapping godoc
@@ -270,6 +505,7 @@ func (c *Controller) getScopes(w http.ResponseWriter, r *http.Request) {
 //	@Param			scopeKey	path		string	true	"Scope key"
 //	@Param			roleID		path		string	true	"Role ID"
 //	@Success		200			{object}	RoleMappingResponse
+//	@Header			200			{string}	ETag	"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
 //	@Failure		400			{object}	ErrorResponse
 //	@Failure		404			{object}	ErrorResponse
 //	@Failure		500			{object}	ErrorResponse
@@ -301,6 +537,10 @@ func (c *Controller) getRoleMapping(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if c.catalogNotModified(w, r) {
+		return
+	}
+
 	response := toRoleMapping(mapping)
 	render.Success(w, http.StatusOK, response)
 }
@@ -308,14 +548,20 @@ func (c *Controller) getRoleMapping(w http.ResponseWriter, r *http.Request) {
 // GetRoleMappings godoc
 //
 //	@Summary		get role mappings
-//	@Description	get all role mappings for a scope
+//	@Description	get all role mappings for a scope, sorted and paged. Filtering by market or partner type narrows the mappings down to the entries applying to it, entries without a filter apply to all.
 //	@Tags			scopes
 //	@Accept			json
 //	@Produce		json
-//	@Param			scopeKey	path		string	true	"Scope key"
-//	@Success		200			{object}	RoleMappingsResponse
-//	@Failure		400			{object}	ErrorResponse
-//	@Failure		500			{object}	ErrorResponse
+//	@Param			scopeKey		path		string	true	"Scope key"
+//	@Param			limit			query		int		false	"Page size, at most 1000, the whole list is returned without a limit"
+//	@Param			offset			query		int		false	"Number of items to skip"
+//	@Param			sort			query		string	false	"Sort field, id, prefixed with - for a descending order"	default(id)
+//	@Param			market			query		string	false	"Only the mapping entries applying to the market"
+//	@Param			partner_type	query		string	false	"Only the mapping entries applying to the partner type"
+//	@Success		200				{object}	RoleMappingsResponse
+//	@Header			200				{string}	ETag	"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
 //	@Router			/iam/scopes/{scopeKey}/mappings [get]
 func (c *Controller) getRoleMappings(w http.ResponseWriter, r *http.Request) {
 	_, span := c.tracer.Start(r.Context(), "controller.getRoleMappings")
@@ -333,8 +579,18 @@ func (c *Controller) getRoleMappings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := toRoleMappings(mappings)
-	render.Success(w, http.StatusOK, response)
+	query, err := listFromQuery(r.URL.Query(), roleMappingSortKeys, "id")
+	if err != nil {
+		c.failure(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if c.catalogNotModified(w, r) {
+		return
+	}
+
+	response, page := paginate(filterRoleMappings(toRoleMappings(mappings), r.URL.Query()), query)
+	success(w, http.StatusOK, response, Meta{Page: page})
 }
 
 // GetUser godoc
@@ -345,10 +601,15 @@ func (c *Controller) getRoleMappings(w http.ResponseWriter, r *http.Request) {
 //	@Accept			json
 //	@Produce		json
 //	@Param			cdsid	path		string	true	"User CDSID"
+//	@Param			fields	query		string	false	"Comma separated sparse fieldset, nested fields are selected with a dot, e.g. id,cdsid,partners.id"
 //	@Success		200		{object}	UserResponse
+//	@Header			200		{string}	X-Data-Stale	"set to true when the user data is served from a stale cache entry"
 //	@Failure		400		{object}	ErrorResponse
 //	@Failure		404		{object}	ErrorResponse
 //	@Failure		500		{object}	ErrorResponse
+//	@Failure		502		{object}	ErrorResponse
+//	@Failure		503		{object}	ErrorResponse
+//	@Failure		504		{object}	ErrorResponse
 //	@Router			/iam/users/{cdsid} [get]
 func (c *Controller) getUser(w http.ResponseWriter, r *http.Request) {
 	ctx, span := c.tracer.Start(r.Context(), "controller.getUser")
@@ -360,19 +621,64 @@ func (c *Controller) getUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := c.authzClient.GetUserByCDSID(ctx, cdsid)
+	c.renderUser(ctx, w, r, plums.ByCDSID(cdsid))
+}
+
+// FindUser godoc
+//
+//	@Summary		find user
+//	@Description	get user by email, PLUMS user ID or identity provider user ID, exactly one user key must be set
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			cdsid				query		string	false	"User CDSID"
+//	@Param			email				query		string	false	"User email"
+//	@Param			user_id				query		string	false	"PLUMS user ID"
+//	@Param			provider			query		string	false	"Identity provider, e.g. AzureAD_VCC, required with provider_user_id"
+//	@Param			provider_user_id	query		string	false	"User ID at the identity provider, e.g. the Azure object ID"
+//	@Param			fields				query		string	false	"Comma separated sparse fieldset, nested fields are selected with a dot, e.g. id,cdsid,partners.id"
+//	@Success		200					{object}	UserResponse
+//	@Header			200					{string}	X-Data-Stale	"set to true when the user data is served from a stale cache entry"
+//	@Failure		400					{object}	ErrorResponse
+//	@Failure		404					{object}	ErrorResponse
+//	@Failure		500					{object}	ErrorResponse
+//	@Failure		502					{object}	ErrorResponse
+//	@Failure		503					{object}	ErrorResponse
+//	@Failure		504					{object}	ErrorResponse
+//	@Router			/iam/users [get]
+func (c *Controller) findUser(w http.ResponseWriter, r *http.Request) {
+	ctx, span := c.tracer.Start(r.Context(), "controller.findUser")
+	defer span.End()
+
+	lookup, err := lookupFromQuery(r.URL.Query())
 	if err != nil {
-		if errors.Is(err, authz.ErrUserNotFound) {
-			c.failure(w, r, http.StatusNotFound, err)
-			return
-		}
+		c.failure(w, r, http.StatusBadRequest, err)
+		return
+	}
 
+	c.renderUser(ctx, w, r, lookup)
+}
+
+func (c *Controller) renderUser(ctx context.Context, w http.ResponseWriter, r *http.Request, lookup plums.Lookup) {
+	fields, err := parseFields(r.URL.Query(), User{})
+	if err != nil {
+		c.failure(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := c.authzClient.GetUser(ctx, lookup)
+	if err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	response, err := fields.apply(toUser(user))
+	if err != nil {
 		c.failure(w, r, http.StatusInternalServerError, err)
 		return
 	}
 
-	response := toUser(user)
-	render.Success(w, http.StatusOK, response)
+	success(w, http.StatusOK, response, userMeta(w, user))
 }
 
 // GetUserAccess godoc
@@ -384,10 +690,18 @@ func (c *Controller) getUser(w http.ResponseWriter, r *http.Request) {
 //	@Produce		json
 //	@Param			cdsid	path		string		true	"User CDSID"
 //	@Param			scope	query		[]string	true	"Scope key"
+//	@Param			context	query		string		false	"Evaluate only the partner context with this PLUMS or cache-manager partner ID"
+//	@Param			primary	query		bool		false	"Evaluate only the primary partner context"
 //	@Success		200		{object}	UserAccessResponse
+//	@Header			200		{string}	X-Data-Stale	"set to true when the user data is served from a stale cache entry"
+//	@Header			200		{string}	ETag			"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
 //	@Failure		400		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
 //	@Failure		404		{object}	ErrorResponse
 //	@Failure		500		{object}	ErrorResponse
+//	@Failure		502		{object}	ErrorResponse
+//	@Failure		503		{object}	ErrorResponse
+//	@Failure		504		{object}	ErrorResponse
 //	@Router			/iam/users/{cdsid}/access [get]
 func (c *Controller) getUserAccess(w http.ResponseWriter, r *http.Request) {
 	ctx, span := c.tracer.Start(r.Context(), "controller.getUserAccess")
@@ -399,25 +713,524 @@ func (c *Controller) getUserAccess(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	c.renderUserAccess(ctx, w, r, plums.ByCDSID(cdsid))
+}
+
+// FindUserAccess godoc
+//
+//	@Summary		find user access
+//	@Description	get all access for a user found by email, PLUMS user ID or identity provider user ID, exactly one user key must be set
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			cdsid				query		string		false	"User CDSID"
+//	@Param			email				query		string		false	"User email"
+//	@Param			user_id				query		string		false	"PLUMS user ID"
+//	@Param			provider			query		string		false	"Identity provider, e.g. AzureAD_VCC, required with provider_user_id"
+//	@Param			provider_user_id	query		string		false	"User ID at the identity provider, e.g. the Azure object ID"
+//	@Param			scope				query		[]string	true	"Scope key"
+//	@Param			context				query		string		false	"Evaluate only the partner context with this PLUMS or cache-manager partner ID"
+//	@Param			primary				query		bool		false	"Evaluate only the primary partner context"
+//	@Success		200					{object}	UserAccessResponse
+//	@Header			200					{string}	X-Data-Stale	"set to true when the user data is served from a stale cache entry"
+//	@Header			200					{string}	ETag			"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+//	@Failure		400					{object}	ErrorResponse
+//	@Failure		403					{object}	ErrorResponse
+//	@Failure		404					{object}	ErrorResponse
+//	@Failure		500					{object}	ErrorResponse
+//	@Failure		502					{object}	ErrorResponse
+//	@Failure		503					{object}	ErrorResponse
+//	@Failure		504					{object}	ErrorResponse
+//	@Router			/iam/users/access [get]
+func (c *Controller) findUserAccess(w http.ResponseWriter, r *http.Request) {
+	ctx, span := c.tracer.Start(r.Context(), "controller.findUserAccess")
+	defer span.End()
+
+	lookup, err := lookupFromQuery(r.URL.Query())
+	if err != nil {
+		c.failure(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	c.renderUserAccess(ctx, w, r, lookup)
+}
+
+func (c *Controller) renderUserAccess(ctx context.Context, w http.ResponseWriter, r *http.Request, lookup plums.Lookup) {
 	scopes := r.URL.Query()["scope"]
 	if len(scopes) == 0 {
 		c.failure(w, r, http.StatusBadRequest, errors.New("field scope is invalid"))
 		return
 	}
 
-	userAccess, err := c.authzClient.GetUserAccess(ctx, cdsid, scopes)
+	opts, err := accessOptions(r.URL.Query())
 	if err != nil {
-		if errors.Is(err, authz.ErrUserNotFound) {
-			c.failure(w, r, http.StatusNotFound, err)
+		c.failure(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := c.authorizeScopes(ctx, scopes); err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	access, err := c.authzClient.GetUserAccess(ctx, lookup, scopes, opts...)
+	if err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	meta := userMeta(w, access.User)
+	if c.accessNotModified(w, r, access) {
+		return
+	}
+
+	response := toUserAccesses(access.Accesses)
+	success(w, http.StatusOK, response, meta)
+}
+
+// PostUsersAccessBatch godoc
+//
+//	@Summary		batch user access
+//	@Description	get the access of many users for the scopes, streamed as one JSON object per line in the order the users complete. Users that fail carry the error instead of their accesses, the stream itself always answers 200 once it started.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		application/x-ndjson
+//	@Param			request	body		UserAccessBatchRequest	true	"Users and scopes"
+//	@Success		200		{object}	UserAccessBatchResult
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		413		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/iam/users/access:batch [post]
+func (c *Controller) postUsersAccessBatch(w http.ResponseWriter, r *http.Request) {
+	ctx, span := c.tracer.Start(r.Context(), "controller.postUsersAccessBatch")
+	defer span.End()
+
+	// the body is limited before it is decoded, so that the number of users is bounded while decoding already
+	body := http.MaxBytesReader(w, r.Body, int64(c.batchMaxUsers)*batchUserBytes+batchBaseBytes)
+
+	var request UserAccessBatchRequest
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.failure(w, r, http.StatusRequestEntityTooLarge, fmt.Errorf("request body is larger than %d bytes", tooLarge.Limit))
 			return
 		}
 
-		c.failure(w, r, http.StatusInternalServerError, err)
+		c.failure(w, r, http.StatusBadRequest, fmt.Errorf("request body is invalid: %w", err))
 		return
 	}
 
-	response := toUserAccesses(userAccess)
-	render.Success(w, http.StatusOK, response)
+	if len(request.CDSIDs) == 0 || slices.Contains(request.CDSIDs, "") {
+		c.failure(w, r, http.StatusBadRequest, errors.New("field cdsids is invalid"))
+		return
+	}
+
+	if len(request.CDSIDs) > c.batchMaxUsers {
+		c.failure(w, r, http.StatusBadRequest, fmt.Errorf("field cdsids holds more than %d users", c.batchMaxUsers))
+		return
+	}
+
+	if len(request.Scopes) == 0 {
+		c.failure(w, r, http.StatusBadRequest, errors.New("field scopes is invalid"))
+		return
+	}
+
+	if err := c.authorizeScopes(ctx, request.Scopes); err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	lookups := make([]plums.Lookup, len(request.CDSIDs))
+	for i, cdsid := range request.CDSIDs {
+		lookups[i] = plums.ByCDSID(cdsid)
+	}
+
+	span.SetAttributes(attribute.Int("batch.users", len(lookups)))
+
+	w.Header().Set("Content-Type", ndjsonContentType)
+	w.WriteHeader(http.StatusOK)
+
+	encoder := json.NewEncoder(w)
+	flusher := http.NewResponseController(w)
+
+	var failed int
+	c.authzClient.GetUsersAccess(ctx, lookups, request.Scopes, c.batchConcurrency, func(result authz.BatchResult) {
+		line := UserAccessBatchResult{CDSID: request.CDSIDs[result.Index]}
+		if result.Err != nil {
+			failed++
+			status, reason := errorStatus(result.Err)
+			line.Error = &Error{Code: status, Message: result.Err.Error(), Reason: reason}
+		} else {
+			line.Accesses = toUserAccesses(result.Access.Accesses)
+			line.Stale = result.Access.User.Stale
+		}
+
+		if err := encoder.Encode(line); err != nil {
+			return
+		}
+		_ = flusher.Flush()
+	})
+
+	span.SetAttributes(attribute.Int("batch.failed", failed))
+}
+
+// GetUserCheck godoc
+//
+//	@Summary		check user access to a target
+//	@Description	check whether a user holds a permission group of a scope for a target partner, distributor or market, exactly one target must be set. NSC contexts also administer the partners under their distributor and in their market, data scopes are decided by their access domains.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			cdsid				path		string	true	"User CDSID"
+//	@Param			scope				query		string	true	"Scope key"
+//	@Param			permission_group	query		string	false	"Permission group, any permission group of the scope when empty, must be empty for data scopes"
+//	@Param			partner				query		string	false	"Target partner, the PARMA code of PARMA partners and the ID of any other partner"
+//	@Param			partner_type		query		string	false	"Type of the target partner, defaults to PARMA"
+//	@Param			distributor			query		string	false	"Target distributor ID"
+//	@Param			market				query		string	false	"Target market"
+//	@Success		200					{object}	DecisionResponse
+//	@Failure		400					{object}	ErrorResponse
+//	@Failure		403					{object}	ErrorResponse
+//	@Failure		404					{object}	ErrorResponse
+//	@Failure		500					{object}	ErrorResponse
+//	@Failure		502					{object}	ErrorResponse
+//	@Failure		503					{object}	ErrorResponse
+//	@Failure		504					{object}	ErrorResponse
+//	@Router			/iam/users/{cdsid}/check [get]
+func (c *Controller) getUserCheck(w http.ResponseWriter, r *http.Request) {
+	ctx, span := c.tracer.Start(r.Context(), "controller.getUserCheck")
+	defer span.End()
+
+	cdsid := chi.URLParam(r, "cdsid")
+	if cdsid == "" {
+		c.failure(w, r, http.StatusBadRequest, errors.New("field user id is invalid"))
+		return
+	}
+
+	c.renderCheck(ctx, w, r, plums.ByCDSID(cdsid))
+}
+
+// FindUserCheck godoc
+//
+//	@Summary		find user and check access to a target
+//	@Description	check whether a user found by email, PLUMS user ID or identity provider user ID holds a permission group of a scope for a target partner, distributor or market, exactly one user key and one target must be set
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			cdsid				query		string	false	"User CDSID"
+//	@Param			email				query		string	false	"User email"
+//	@Param			user_id				query		string	false	"PLUMS user ID"
+//	@Param			provider			query		string	false	"Identity provider, e.g. AzureAD_VCC, required with provider_user_id"
+//	@Param			provider_user_id	query		string	false	"User ID at the identity provider, e.g. the Azure object ID"
+//	@Param			scope				query		string	true	"Scope key"
+//	@Param			permission_group	query		string	false	"Permission group, any permission group of the scope when empty, must be empty for data scopes"
+//	@Param			partner				query		string	false	"Target partner, the PARMA code of PARMA partners and the ID of any other partner"
+//	@Param			partner_type		query		string	false	"Type of the target partner, defaults to PARMA"
+//	@Param			distributor			query		string	false	"Target distributor ID"
+//	@Param			market				query		string	false	"Target market"
+//	@Success		200					{object}	DecisionResponse
+//	@Failure		400					{object}	ErrorResponse
+//	@Failure		403					{object}	ErrorResponse
+//	@Failure		404					{object}	ErrorResponse
+//	@Failure		500					{object}	ErrorResponse
+//	@Failure		502					{object}	ErrorResponse
+//	@Failure		503					{object}	ErrorResponse
+//	@Failure		504					{object}	ErrorResponse
+//	@Router			/iam/users/check [get]
+func (c *Controller) findUserCheck(w http.ResponseWriter, r *http.Request) {
+	ctx, span := c.tracer.Start(r.Context(), "controller.findUserCheck")
+	defer span.End()
+
+	lookup, err := lookupFromQuery(r.URL.Query())
+	if err != nil {
+		c.failure(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	c.renderCheck(ctx, w, r, lookup)
+}
+
+func (c *Controller) renderCheck(ctx context.Context, w http.ResponseWriter, r *http.Request, lookup plums.Lookup) {
+	query := r.URL.Query()
+	scope := query.Get(scopeParam)
+	if scope == "" {
+		c.failure(w, r, http.StatusBadRequest, errors.New("field scope is invalid"))
+		return
+	}
+
+	target, err := targetFromQuery(query)
+	if err != nil {
+		c.failure(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := c.authorizeScopes(ctx, []string{scope}); err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	decision, err := c.authzClient.Check(ctx, lookup, scope, query.Get(permissionGroupParam), target)
+	if err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	render.Success(w, http.StatusOK, toDecision(decision))
+}
+
+// GetMe godoc
+//
+//	@Summary		get me
+//	@Description	get the user of the bearer token
+//	@Tags			me
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			fields	query		string	false	"Comma separated sparse fieldset, nested fields are selected with a dot, e.g. id,cdsid,partners.id"
+//	@Success		200		{object}	UserResponse
+//	@Header			200		{string}	X-Data-Stale	"set to true when the user data is served from a stale cache entry"
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Failure		502		{object}	ErrorResponse
+//	@Failure		503		{object}	ErrorResponse
+//	@Failure		504		{object}	ErrorResponse
+//	@Router			/iam/me [get]
+func (c *Controller) getMe(w http.ResponseWriter, r *http.Request) {
+	ctx, span := c.tracer.Start(r.Context(), "controller.getMe")
+	defer span.End()
+
+	lookup, err := c.subject(ctx)
+	if err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	c.renderUser(ctx, w, r, lookup)
+}
+
+// GetMeAccess godoc
+//
+//	@Summary		get my access
+//	@Description	get all access for the user of the bearer token
+//	@Tags			me
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			scope	query		[]string	true	"Scope key"
+//	@Param			context	query		string		false	"Evaluate only the partner context with this PLUMS or cache-manager partner ID"
+//	@Param			primary	query		bool		false	"Evaluate only the primary partner context"
+//	@Success		200		{object}	UserAccessResponse
+//	@Header			200		{string}	X-Data-Stale	"set to true when the user data is served from a stale cache entry"
+//	@Header			200		{string}	ETag			"entity tag of the response, sent as If-None-Match it is answered with 304 Not Modified while the response is unchanged"
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Failure		502		{object}	ErrorResponse
+//	@Failure		503		{object}	ErrorResponse
+//	@Failure		504		{object}	ErrorResponse
+//	@Router			/iam/me/access [get]
+func (c *Controller) getMeAccess(w http.ResponseWriter, r *http.Request) {
+	ctx, span := c.tracer.Start(r.Context(), "controller.getMeAccess")
+	defer span.End()
+
+	lookup, err := c.subject(ctx)
+	if err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	c.renderUserAccess(ctx, w, r, lookup)
+}
+
+// PostToken godoc
+//
+//	@Summary		create token
+//	@Description	mint a short-lived token carrying the access of the user for the scopes of the calling client, verified with the keys of /.well-known/jwks.json
+//	@Tags			token
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		TokenRequest	false	"User and scopes of the token, default to the subject of the bearer token and the dependant scopes of the client"
+//	@Success		200		{object}	TokenResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		422		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Failure		502		{object}	ErrorResponse
+//	@Failure		503		{object}	ErrorResponse
+//	@Failure		504		{object}	ErrorResponse
+//	@Router			/iam/token [post]
+func (c *Controller) postToken(w http.ResponseWriter, r *http.Request) {
+	ctx, span := c.tracer.Start(r.Context(), "controller.postToken")
+	defer span.End()
+
+	var request TokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		c.failure(w, r, http.StatusBadRequest, fmt.Errorf("request body is invalid: %w", err))
+		return
+	}
+
+	principal, ok := auth.FromContext(ctx)
+	if !ok || c.authenticator == nil {
+		c.serviceFailure(w, r, auth.ErrNotAuthenticated)
+		return
+	}
+
+	// tokens are minted for a user on behalf of a client, so the caller must carry both
+	if principal.ClientID == "" {
+		c.serviceFailure(w, r, auth.ErrMissingClient)
+		return
+	}
+
+	lookup, err := c.authenticator.Lookup(principal)
+	if err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	// only privileged clients may mint tokens of other users, whether or not the user endpoints are restricted
+	if request.CDSID != "" {
+		foreign := plums.ByCDSID(request.CDSID)
+		if !c.authenticator.Owns(principal, foreign) && !c.authenticator.Privileged(principal) {
+			c.serviceFailure(w, r, fmt.Errorf("%w: [%s]", auth.ErrNotPrivileged, principal.ClientID))
+			return
+		}
+		lookup = foreign
+	}
+
+	if err := c.authorizeClientScopes(ctx, principal.ClientID, request.Scopes); err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	access, err := c.authzClient.GetClientUserAccess(ctx, principal.ClientID, lookup, request.Scopes)
+	if err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	// the CDSID is the subject of the token
+	if access.User.CDSID == "" {
+		c.serviceFailure(w, r, fmt.Errorf("%w: [%s]", errCDSIDUnresolved, access.User.ID))
+		return
+	}
+
+	signed, err := c.tokenSigner.Sign(access.User.CDSID, principal.ClientID, map[string]any{
+		"azp":    principal.ClientID,
+		"access": toUserAccesses(access.Accesses),
+	})
+	if err != nil {
+		c.serviceFailure(w, r, err)
+		return
+	}
+
+	response := toToken(signed)
+	success(w, http.StatusOK, response, userMeta(w, access.User))
+}
+
+// subject returns the user lookup of the authenticated caller.
+func (c *Controller) subject(ctx context.Context) (plums.Lookup, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || c.authenticator == nil {
+		return plums.Lookup{}, auth.ErrNotAuthenticated
+	}
+
+	return c.authenticator.Lookup(principal)
+}
+
+// privileged only lets privileged clients query arbitrary users, when the authenticator restricts the user endpoints.
+func (c *Controller) privileged(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.authenticator == nil || !c.authenticator.RestrictUserRoutes() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, ok := auth.FromContext(r.Context())
+		if !ok {
+			c.serviceFailure(w, r, auth.ErrNotAuthenticated)
+			return
+		}
+
+		if !c.authenticator.Privileged(principal) {
+			c.serviceFailure(w, r, fmt.Errorf("%w: [%s]", auth.ErrNotPrivileged, principal.ClientID))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorizeScopes limits an authenticated caller to the dependant scopes of its client, anonymous callers are only
+// let through by the authenticator when authentication is not required.
+func (c *Controller) authorizeScopes(ctx context.Context, scopes []string) error {
+	if c.authenticator == nil || !c.authenticator.EnforceClientScopes() {
+		return nil
+	}
+
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	caller, err := c.authzStore.GetClient(principal.ClientID)
+	if err != nil {
+		if errors.Is(err, store.ErrClientNotFound) {
+			return fmt.Errorf("%w: [%s]", auth.ErrUnknownClient, principal.ClientID)
+		}
+		return err
+	}
+
+	for _, scope := range scopes {
+		if !slices.Contains(caller.DependantScopes, scope) {
+			return fmt.Errorf("%w: [%s] is not a dependant scope of the calling client [%s]", authz.ErrScopeNotAllowed, scope, caller.ID)
+		}
+	}
+
+	return nil
+}
+
+// authorizeClientScopes authorizes the scopes of a client access request, which default to the dependant scopes of
+// the requested client.
+func (c *Controller) authorizeClientScopes(ctx context.Context, clientID string, scopes []string) error {
+	if len(scopes) == 0 && c.authenticator != nil && c.authenticator.EnforceClientScopes() {
+		client, err := c.authzStore.GetClient(clientID)
+		if err != nil {
+			// an unknown client is reported by the evaluation
+			return nil
+		}
+		scopes = client.DependantScopes
+	}
+
+	return c.authorizeScopes(ctx, scopes)
+}
+
+// serviceFailure renders a failure of the authz service, upstream errors are mapped to their own status and reason.
+func (c *Controller) serviceFailure(w http.ResponseWriter, r *http.Request, err error) {
+	span := trace.SpanFromContext(r.Context())
+	span.SetStatus(codes.Error, err.Error())
+	span.RecordError(err)
+
+	status, reason := errorStatus(err)
+	if status == http.StatusForbidden && c.authenticator != nil {
+		principal, _ := auth.FromContext(r.Context())
+		c.authenticator.ObserveDenied(principal.ClientID, reason)
+	}
+
+	writeJSON(w, status, ErrorResponse{
+		Error: Error{
+			Code:    status,
+			Message: err.Error(),
+			Reason:  reason,
+		},
+	})
 }
 
 func (c *Controller) failure(w http.ResponseWriter, r *http.Request, status int, err error) {
@@ -1,8 +1,11 @@
 package v1
 
 import (
+	"time"
+
 	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
 	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/token"
 )
 
 func toClient(client store.Client) Client {
@@ -47,6 +50,7 @@ func toScope(scope store.Scope) Scope {
 		Key:              scope.Key,
 		Label:            scope.Label,
 		Description:      scope.Description,
+		Type:             scope.Type.String(),
 		PermissionGroups: permissionGroups,
 	}
 }
@@ -87,6 +91,8 @@ func toRoleMapping(mapping store.RoleMapping) RoleMapping {
 				PartnerType: m.Filter.PartnerType,
 			},
 			PermissionGroups: m.PermissionGroups,
+			Domains:          toDomains(m.Domains),
+			Inherit:          m.Inherit,
 		}
 	}
 
@@ -96,6 +102,19 @@ func toRoleMapping(mapping store.RoleMapping) RoleMapping {
 	}
 }
 
+func toDomains(domains []store.Domain) []string {
+	if len(domains) == 0 {
+		return nil
+	}
+
+	arr := make([]string, len(domains))
+	for i, domain := range domains {
+		arr[i] = domain.String()
+	}
+
+	return arr
+}
+
 func toRoleMappings(mappings []store.RoleMapping) []RoleMapping {
 	arr := make([]RoleMapping, len(mappings))
 	for i, mapping := range mappings {
@@ -108,14 +127,32 @@ func toRoleMappings(mappings []store.RoleMapping) []RoleMapping {
 func toUser(user authz.User) User {
 	partners := toPartners(user.Partners)
 	return User{
-		ID:          user.ID,
-		Email:       user.Email,
-		CDSID:       user.CDSID,
-		CountryCode: user.CountryCode,
-		Partners:    partners,
+		ID:                 user.ID,
+		FirstName:          user.FirstName,
+		LastName:           user.LastName,
+		Email:              user.Email,
+		CDSID:              user.CDSID,
+		CDSIDUnresolved:    user.CDSIDUnresolved,
+		CountryCode:        user.CountryCode,
+		Partners:           partners,
+		Identities:         toIdentities(user.Identities),
+		UnresolvedPartners: user.UnresolvedPartners,
 	}
 }
 
+func toIdentities(identities []authz.Identity) []Identity {
+	arr := make([]Identity, len(identities))
+	for i, identity := range identities {
+		arr[i] = Identity{
+			Provider:       identity.Provider,
+			ProviderUserID: identity.ProviderUserID,
+			AccountName:    identity.AccountName,
+		}
+	}
+
+	return arr
+}
+
 func toPartner(partner authz.Partner) Partner {
 	return Partner{
 		ID:               partner.ID,
@@ -126,7 +163,30 @@ func toPartner(partner authz.Partner) Partner {
 		ParmaPartnerCode: partner.ParmaPartnerCode,
 		Market:           partner.Market,
 		Active:           partner.Active,
+		IsPrimary:        partner.IsPrimary,
 		Roles:            partner.Roles,
+		Address:          toAddress(partner.Address),
+	}
+}
+
+// toAddress returns nil for partners without an address, so that it is omitted from the response.
+func toAddress(address authz.Address) *Address {
+	if address == (authz.Address{}) {
+		return nil
+	}
+
+	return &Address{
+		CountryName:  address.CountryName,
+		AddressLine1: address.AddressLine1,
+		AddressLine2: address.AddressLine2,
+		AddressLine3: address.AddressLine3,
+		AddressLine4: address.AddressLine4,
+		City:         address.City,
+		District:     address.District,
+		State:        address.State,
+		PostalCode:   address.PostalCode,
+		CountryCode:  address.CountryCode,
+		LanguageCode: address.LanguageCode,
 	}
 }
 
@@ -141,14 +201,38 @@ func toPartners(partners []authz.Partner) []Partner {
 
 func toUserAccess(access authz.UserAccess) UserAccess {
 	return UserAccess{
-		Context: Context{
-			ID:   access.Context.ID,
-			Type: access.Context.Type,
-			Tag:  access.Context.Tag,
-		},
+		Context:          toContext(access.Context),
 		Roles:            access.Roles,
 		PermissionGroups: access.PermissionGroups,
+		Domains:          toAccessDomains(access.Domains),
+	}
+}
+
+func toContext(context authz.Context) Context {
+	return Context{
+		ID:            context.ID,
+		Type:          context.Type,
+		Tag:           context.Tag,
+		Inherited:     context.Inherited,
+		InheritedFrom: context.InheritedFrom,
+	}
+}
+
+func toAccessDomains(domains map[string]authz.AccessDomain) map[string]AccessDomain {
+	if len(domains) == 0 {
+		return nil
+	}
+
+	m := make(map[string]AccessDomain, len(domains))
+	for scope, domain := range domains {
+		m[scope] = AccessDomain{
+			Partners:     domain.Partners,
+			Distributors: domain.Distributors,
+			Markets:      domain.Markets,
+		}
 	}
+
+	return m
 }
 
 func toUserAccesses(accesses []authz.UserAccess) []UserAccess {
@@ -159,3 +243,51 @@ func toUserAccesses(accesses []authz.UserAccess) []UserAccess {
 
 	return arr
 }
+
+func toClientUserAccess(access authz.ClientAccess) ClientUserAccess {
+	return ClientUserAccess{
+		Client:   toClient(access.Client),
+		Accesses: toUserAccesses(access.Accesses),
+	}
+}
+
+func toDecision(decision authz.Decision) Decision {
+	var context *Context
+	if decision.Context != nil {
+		c := toContext(*decision.Context)
+		context = &c
+	}
+
+	return Decision{
+		Allowed: decision.Allowed,
+		Reason:  decision.Reason.String(),
+		Context: context,
+	}
+}
+
+func toToken(signed token.Token) Token {
+	return Token{
+		AccessToken: signed.Value,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(signed.ExpiresAt).Seconds()),
+	}
+}
+
+func toConfigChange(change store.Change) ConfigChange {
+	return ConfigChange{
+		Revision:         change.Revision,
+		Digest:           change.Digest,
+		Scopes:           toChangeSet(change.Scopes),
+		Roles:            toChangeSet(change.Roles),
+		Mappings:         toChangeSet(change.RoleMappings),
+		PermissionGroups: toChangeSet(change.PermissionGroups),
+	}
+}
+
+func toChangeSet(diff store.Diff) ChangeSet {
+	return ChangeSet{
+		Added:   diff.Added,
+		Removed: diff.Removed,
+		Changed: diff.Changed,
+	}
+}
@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/auth"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/reasons"
+)
+
+// The reasons of the responses, see package reasons.
+const (
+	ReasonUserNotFound              = reasons.UserNotFound
+	ReasonContextNotFound           = reasons.ContextNotFound
+	ReasonClientNotFound            = reasons.ClientNotFound
+	ReasonScopeNotAllowed           = reasons.ScopeNotAllowed
+	ReasonUnauthenticated           = reasons.Unauthenticated
+	ReasonClientNotPrivileged       = reasons.ClientNotPrivileged
+	ReasonClientNotRegistered       = reasons.ClientNotRegistered
+	ReasonInvalidTarget             = reasons.InvalidTarget
+	ReasonInvalidCheck              = reasons.InvalidCheck
+	ReasonInvalidUser               = reasons.InvalidUser
+	ReasonCDSIDUnresolved           = reasons.CDSIDUnresolved
+	ReasonTargetNotFound            = reasons.TargetNotFound
+	ReasonUpstreamNotFound          = reasons.UpstreamNotFound
+	ReasonUpstreamUnauthorized      = reasons.UpstreamUnauthorized
+	ReasonUpstreamUnavailable       = reasons.UpstreamUnavailable
+	ReasonUpstreamTimeout           = reasons.UpstreamTimeout
+	ReasonUpstreamMalformedResponse = reasons.UpstreamMalformedResponse
+	ReasonInternal                  = reasons.Internal
+)
+
+// errCDSIDUnresolved is returned for tokens of users whose CDSID could not be resolved, the CDSID is their subject.
+var errCDSIDUnresolved = errors.New("CDSID of the user is unresolved")
+
+// errorStatus maps an error of the authz service to the HTTP status and reason of the response.
+func errorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, authz.ErrUserNotFound):
+		return http.StatusNotFound, ReasonUserNotFound
+	case errors.Is(err, authz.ErrContextNotFound):
+		return http.StatusNotFound, ReasonContextNotFound
+	case errors.Is(err, authz.ErrClientNotFound):
+		return http.StatusNotFound, ReasonClientNotFound
+	case errors.Is(err, authz.ErrScopeNotAllowed):
+		return http.StatusForbidden, ReasonScopeNotAllowed
+	case errors.Is(err, auth.ErrNotAuthenticated), errors.Is(err, auth.ErrMissingSubject), errors.Is(err, auth.ErrMissingClient):
+		return http.StatusUnauthorized, ReasonUnauthenticated
+	case errors.Is(err, auth.ErrNotPrivileged):
+		return http.StatusForbidden, ReasonClientNotPrivileged
+	case errors.Is(err, auth.ErrUnknownClient):
+		return http.StatusForbidden, ReasonClientNotRegistered
+	case errors.Is(err, errCDSIDUnresolved):
+		return http.StatusUnprocessableEntity, ReasonCDSIDUnresolved
+	case errors.Is(err, plums.ErrInvalidLookup):
+		return http.StatusBadRequest, ReasonInvalidUser
+	case errors.Is(err, authz.ErrInvalidTarget):
+		return http.StatusBadRequest, ReasonInvalidTarget
+	case errors.Is(err, authz.ErrInvalidCheck):
+		return http.StatusBadRequest, ReasonInvalidCheck
+	case errors.Is(err, authz.ErrTargetNotFound):
+		return http.StatusNotFound, ReasonTargetNotFound
+	case errors.Is(err, gateway.ErrNotFound):
+		return http.StatusNotFound, ReasonUpstreamNotFound
+	case errors.Is(err, gateway.ErrTimeout):
+		return http.StatusGatewayTimeout, ReasonUpstreamTimeout
+	case errors.Is(err, gateway.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable, ReasonUpstreamUnavailable
+	case errors.Is(err, gateway.ErrUnauthorized):
+		return http.StatusBadGateway, ReasonUpstreamUnauthorized
+	case errors.Is(err, gateway.ErrMalformedResponse):
+		return http.StatusBadGateway, ReasonUpstreamMalformedResponse
+	default:
+		return http.StatusInternalServerError, ReasonInternal
+	}
+}
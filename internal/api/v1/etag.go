@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+)
+
+const (
+	// catalogCacheControl lets clients keep the catalog, they revalidate it with its entity tag before every use.
+	catalogCacheControl = "no-cache"
+	// accessCacheControl keeps the access of users out of shared caches.
+	accessCacheControl = "private, no-cache"
+)
+
+// entityTag returns a strong entity tag of the parts.
+func entityTag(parts ...any) string {
+	hash := sha256.New()
+	encoder := json.NewEncoder(hash)
+	for _, part := range parts {
+		_ = encoder.Encode(part)
+	}
+
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// notModified sets the entity tag and cache policy of a response and answers 304 Not Modified when the If-None-Match
+// header of the request holds the tag, in which case it reports true and nothing else must be written.
+func notModified(w http.ResponseWriter, r *http.Request, tag, cacheControl string) bool {
+	w.Header().Set("ETag", tag)
+	w.Header().Set("Cache-Control", cacheControl)
+
+	if !matchesTag(r.Header.Get("If-None-Match"), tag) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// matchesTag compares the tags of an If-None-Match header weakly with the tag, as RFC 9110 requires.
+func matchesTag(header, tag string) bool {
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// catalogNotModified answers a conditional request of a catalog endpoint, which only changes with the content of the
+// store and the filters and page of the query. Handlers call it once the resource is resolved and the query is valid,
+// so that errors carry no entity tag.
+func (c *Controller) catalogNotModified(w http.ResponseWriter, r *http.Request) bool {
+	return notModified(w, r, entityTag(c.authzStore.Digest(), canonicalQuery(r.URL.Query())), catalogCacheControl)
+}
+
+// canonicalQuery encodes the query with its keys and the values of every key sorted.
+func canonicalQuery(query url.Values) string {
+	sorted := make(url.Values, len(query))
+	for key, values := range query {
+		sorted[key] = slices.Clone(values)
+		slices.Sort(sorted[key])
+	}
+
+	return sorted.Encode()
+}
+
+// accessNotModified answers a conditional request of the access of a user. The tag is computed from a canonical copy
+// of the access, since partners and contexts are evaluated in no particular order.
+func (c *Controller) accessNotModified(w http.ResponseWriter, r *http.Request, access authz.Access) bool {
+	return notModified(w, r, entityTag(c.authzStore.Digest(), canonicalAccess(access)), accessCacheControl)
+}
+
+// canonicalAccess returns a copy of the access with its lists sorted.
+func canonicalAccess(access authz.Access) authz.Access {
+	user := access.User
+	user.Partners = slices.Clone(user.Partners)
+	slices.SortFunc(user.Partners, func(a, b authz.Partner) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	user.Identities = slices.Clone(user.Identities)
+	slices.SortFunc(user.Identities, func(a, b authz.Identity) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.ProviderUserID, b.ProviderUserID))
+	})
+	user.UnresolvedPartners = slices.Clone(user.UnresolvedPartners)
+	slices.Sort(user.UnresolvedPartners)
+
+	accesses := make([]authz.UserAccess, len(access.Accesses))
+	for i, userAccess := range access.Accesses {
+		userAccess.Roles = slices.Clone(userAccess.Roles)
+		slices.Sort(userAccess.Roles)
+		accesses[i] = userAccess
+	}
+	slices.SortFunc(accesses, func(a, b authz.UserAccess) int {
+		return cmp.Or(cmp.Compare(a.Context.ID, b.Context.ID), cmp.Compare(a.Context.Type, b.Context.Type))
+	})
+
+	return authz.Access{User: user, Accesses: accesses}
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/events"
+)
+
+// eventStreamContentType is the content type of server-sent events.
+const eventStreamContentType = "text/event-stream"
+
+const defaultHeartbeatInterval = 30 * time.Second
+
+type eventBroker interface {
+	Subscribe(lastEventID string) (replay []events.Event, stream <-chan events.Event, cancel func())
+}
+
+// GetEvents godoc
+//
+//	@Summary		configuration events
+//	@Description	stream the changes of the IAM configuration as server-sent events. A config.revision event carrying a ConfigChange is sent for every new revision. Reconnecting with the Last-Event-ID header replays the missed events, or a single config.reset event when they are no longer buffered, after which everything derived from the configuration must be fetched again.
+//	@Tags			events
+//	@Produce		text/event-stream
+//	@Param			Last-Event-ID	header		string	false	"ID of the last received event"
+//	@Success		200				{object}	ConfigChange
+//	@Router			/iam/events [get]
+func (c *Controller) getEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	replay, stream, cancel := c.events.Subscribe(r.Header.Get("Last-Event-ID"))
+	defer cancel()
+
+	controller := http.NewResponseController(w)
+	// the stream outlives the write timeout of the server
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", eventStreamContentType)
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_ = controller.Flush()
+
+	for _, event := range replay {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	_ = controller.Flush()
+
+	heartbeat := time.NewTicker(c.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-stream:
+			if !ok {
+				// the stream fell behind, the client resumes it with the Last-Event-ID
+				return
+			}
+
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		_ = controller.Flush()
+	}
+}
+
+func writeEvent(w io.Writer, event events.Event) error {
+	data, err := json.Marshal(toConfigChange(event.Change))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
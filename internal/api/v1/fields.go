@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+const (
+	fieldsParam     = "fields"
+	fieldsSeparator = ","
+	fieldsNesting   = "."
+)
+
+// fieldSet is a sparse fieldset, a field without children selects the whole value.
+type fieldSet map[string]fieldSet
+
+// parseFields reads the ?fields= sparse fieldset, e.g. fields=id,cdsid,partners.id, and checks every field
+// against the JSON fields of model. An empty fieldset selects all fields.
+func parseFields(query url.Values, model any) (fieldSet, error) {
+	raw := query.Get(fieldsParam)
+	if raw == "" {
+		return nil, nil
+	}
+
+	fields := make(fieldSet)
+	for _, field := range strings.Split(raw, fieldsSeparator) {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		path := strings.Split(field, fieldsNesting)
+		if !hasField(reflect.TypeOf(model), path) {
+			return nil, fmt.Errorf("field [%s] is unknown", field)
+		}
+
+		fields.add(path)
+	}
+
+	return fields, nil
+}
+
+func (f fieldSet) add(path []string) {
+	children, ok := f[path[0]]
+	if ok && children == nil {
+		// the whole value is already selected
+		return
+	}
+
+	if len(path) == 1 {
+		f[path[0]] = nil
+		return
+	}
+
+	if children == nil {
+		children = make(fieldSet)
+		f[path[0]] = children
+	}
+	children.add(path[1:])
+}
+
+// apply reduces v to the selected fields, v is rendered as JSON first so that the fields match the response.
+func (f fieldSet) apply(v any) (any, error) {
+	if len(f) == 0 {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	return f.filter(generic), nil
+}
+
+func (f fieldSet) filter(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		filtered := make(map[string]any, len(f))
+		for name, children := range f {
+			field, ok := value[name]
+			if !ok {
+				continue
+			}
+
+			if children == nil {
+				filtered[name] = field
+				continue
+			}
+			filtered[name] = children.filter(field)
+		}
+		return filtered
+	case []any:
+		for i, item := range value {
+			value[i] = f.filter(item)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// hasField reports whether the JSON field path exists in typ, slices and pointers are looked through.
+func hasField(typ reflect.Type, path []string) bool {
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+
+	if len(path) == 0 {
+		return true
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == path[0] {
+			return hasField(field.Type, path[1:])
+		}
+	}
+
+	return false
+}
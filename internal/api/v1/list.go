@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	limitParam          = "limit"
+	offsetParam         = "offset"
+	sortParam           = "sort"
+	nameParam           = "name"
+	typeParam           = "type"
+	dependantScopeParam = "dependant_scope"
+)
+
+// maxLimit bounds the page size, lists without a limit are returned in full.
+const maxLimit = 1000
+
+// sortKeys are the fields a list can be sorted by.
+type sortKeys[T any] map[string]func(T) string
+
+// listQuery is the order and page of a list endpoint.
+type listQuery[T any] struct {
+	limit  int
+	offset int
+	key    func(T) string
+	desc   bool
+	// tiebreak orders items with the same sort key by the default key.
+	tiebreak func(T) string
+}
+
+// listFromQuery reads the limit, offset and sort of a list endpoint, sort is a field of keys prefixed with - for a
+// descending order and defaults to the ascending defaultKey.
+func listFromQuery[T any](query url.Values, keys sortKeys[T], defaultKey string) (listQuery[T], error) {
+	q := listQuery[T]{key: keys[defaultKey], tiebreak: keys[defaultKey]}
+
+	if raw := query.Get(limitParam); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return listQuery[T]{}, fmt.Errorf("field limit is invalid, it must be between 1 and %d", maxLimit)
+		}
+		q.limit = limit
+	}
+
+	if raw := query.Get(offsetParam); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return listQuery[T]{}, errors.New("field offset is invalid")
+		}
+		q.offset = offset
+	}
+
+	if raw := query.Get(sortParam); raw != "" {
+		field, desc := strings.CutPrefix(raw, "-")
+		key, ok := keys[field]
+		if !ok {
+			return listQuery[T]{}, fmt.Errorf("field sort is invalid, it must be one of [%s]", strings.Join(sortedKeys(keys), ", "))
+		}
+		q.key, q.desc = key, desc
+	}
+
+	return q, nil
+}
+
+// paginate sorts the items and returns the requested page of them with its metadata.
+func paginate[T any](items []T, q listQuery[T]) ([]T, *Page) {
+	slices.SortFunc(items, func(a, b T) int {
+		c := strings.Compare(q.key(a), q.key(b))
+		if q.desc {
+			c = -c
+		}
+		if c == 0 {
+			c = strings.Compare(q.tiebreak(a), q.tiebreak(b))
+		}
+
+		return c
+	})
+
+	total := len(items)
+	start := min(q.offset, total)
+	end := total
+	if q.limit > 0 {
+		end = min(start+q.limit, total)
+	}
+
+	return items[start:end], &Page{Total: total, Offset: q.offset, Limit: q.limit}
+}
+
+func sortedKeys[T any](keys sortKeys[T]) []string {
+	arr := make([]string, 0, len(keys))
+	for key := range keys {
+		arr = append(arr, key)
+	}
+	slices.Sort(arr)
+
+	return arr
+}
+
+var (
+	clientSortKeys = sortKeys[Client]{
+		"id":   func(c Client) string { return c.ID },
+		"name": func(c Client) string { return c.Name },
+	}
+	roleSortKeys = sortKeys[Role]{
+		"id":   func(r Role) string { return r.ID },
+		"name": func(r Role) string { return r.Name },
+	}
+	scopeSortKeys = sortKeys[Scope]{
+		"key":   func(s Scope) string { return s.Key },
+		"label": func(s Scope) string { return s.Label },
+		"type":  func(s Scope) string { return s.Type },
+	}
+	roleMappingSortKeys = sortKeys[RoleMapping]{
+		"id": func(m RoleMapping) string { return m.RoleID },
+	}
+)
+
+// filterClients keeps the clients that depend on the dependant_scope of the query.
+func filterClients(clients []Client, query url.Values) []Client {
+	if scope := query.Get(dependantScopeParam); scope != "" {
+		clients = slices.DeleteFunc(clients, func(c Client) bool { return !slices.Contains(c.DependantScopes, scope) })
+	}
+
+	return clients
+}
+
+// filterRoles keeps the roles whose name contains the name of the query, ignoring case.
+func filterRoles(roles []Role, query url.Values) []Role {
+	if name := strings.ToLower(query.Get(nameParam)); name != "" {
+		roles = slices.DeleteFunc(roles, func(r Role) bool { return !strings.Contains(strings.ToLower(r.Name), name) })
+	}
+
+	return roles
+}
+
+// filterScopes keeps the scopes of the type of the query.
+func filterScopes(scopes []Scope, query url.Values) []Scope {
+	if typ := query.Get(typeParam); typ != "" {
+		scopes = slices.DeleteFunc(scopes, func(s Scope) bool { return !strings.EqualFold(s.Type, typ) })
+	}
+
+	return scopes
+}
+
+// filterRoleMappings narrows the mappings down to the entries that apply to the market and partner type of the query,
+// entries without a market or partner type filter apply to all of them. Role mappings without such entries are
+// dropped.
+func filterRoleMappings(mappings []RoleMapping, query url.Values) []RoleMapping {
+	market, partnerType := query.Get(marketParam), query.Get(partnerTypeParam)
+	if market == "" && partnerType == "" {
+		return mappings
+	}
+
+	applies := func(values []string, value string) bool {
+		return value == "" || len(values) == 0 || slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) })
+	}
+
+	filtered := make([]RoleMapping, 0, len(mappings))
+	for _, mapping := range mappings {
+		var entries []Mapping
+		for _, entry := range mapping.Mapping {
+			if applies(entry.Filter.Market, market) && applies(entry.Filter.PartnerType, partnerType) {
+				entries = append(entries, entry)
+			}
+		}
+
+		if len(entries) > 0 {
+			filtered = append(filtered, RoleMapping{RoleID: mapping.RoleID, Mapping: entries})
+		}
+	}
+
+	return filtered
+}
@@ -18,6 +18,7 @@ type Scope struct {
 	Key              string            `json:"key,omitempty"`
 	Label            string            `json:"label,omitempty"`
 	Description      string            `json:"description,omitempty"`
+	Type             string            `json:"type,omitempty"`
 	PermissionGroups []PermissionGroup `json:"permission_groups,omitempty"`
 } // @name Scope
 
@@ -35,6 +36,8 @@ type RoleMapping struct {
 type Mapping struct {
 	Filter           Filter   `json:"filter,omitempty"`
 	PermissionGroups []string `json:"permission_groups,omitempty"`
+	Domains          []string `json:"domains,omitempty"`
+	Inherit          bool     `json:"inherit,omitempty"`
 } // @name Mapping
 
 type Filter struct {
@@ -44,13 +47,24 @@ type Filter struct {
 } // @name Filter
 
 type User struct {
-	ID          string    `json:"id,omitempty"`
-	Email       string    `json:"email,omitempty"`
-	CDSID       string    `json:"cdsid,omitempty"`
-	CountryCode string    `json:"country_code,omitempty"`
-	Partners    []Partner `json:"partners,omitempty"`
+	ID                 string     `json:"id,omitempty"`
+	FirstName          string     `json:"first_name,omitempty"`
+	LastName           string     `json:"last_name,omitempty"`
+	Email              string     `json:"email,omitempty"`
+	CDSID              string     `json:"cdsid,omitempty"`
+	CDSIDUnresolved    bool       `json:"cdsid_unresolved,omitempty"`
+	CountryCode        string     `json:"country_code,omitempty"`
+	Partners           []Partner  `json:"partners,omitempty"`
+	Identities         []Identity `json:"identities,omitempty"`
+	UnresolvedPartners []string   `json:"unresolved_partners,omitempty"`
 } // @name User
 
+type Identity struct {
+	Provider       string `json:"provider,omitempty"`
+	ProviderUserID string `json:"provider_user_id,omitempty"`
+	AccountName    string `json:"account_name,omitempty"`
+} // @name Identity
+
 type Partner struct {
 	ID               string   `json:"id,omitempty"`
 	RoleCode         string   `json:"role_code,omitempty"`
@@ -60,17 +74,97 @@ type Partner struct {
 	ParmaPartnerCode string   `json:"parma_partner_code,omitempty"`
 	Market           string   `json:"market,omitempty"`
 	Active           bool     `json:"active,omitempty"`
+	IsPrimary        bool     `json:"is_primary,omitempty"`
 	Roles            []string `json:"roles,omitempty"`
+	Address          *Address `json:"address,omitempty"`
 } // @name Partner
 
+type Address struct {
+	CountryName  string `json:"country_name,omitempty"`
+	AddressLine1 string `json:"address_line_1,omitempty"`
+	AddressLine2 string `json:"address_line_2,omitempty"`
+	AddressLine3 string `json:"address_line_3,omitempty"`
+	AddressLine4 string `json:"address_line_4,omitempty"`
+	City         string `json:"city,omitempty"`
+	District     string `json:"district,omitempty"`
+	State        string `json:"state,omitempty"`
+	PostalCode   string `json:"postal_code,omitempty"`
+	CountryCode  string `json:"country_code,omitempty"`
+	LanguageCode string `json:"language_code,omitempty"`
+} // @name Address
+
 type UserAccess struct {
 	Context          Context             `json:"context,omitempty"`
 	Roles            []string            `json:"roles,omitempty"`
 	PermissionGroups map[string][]string `json:"permission_groups,omitempty"`
+	// Domains are the access domains of the data scopes, keyed by scope.
+	Domains map[string]AccessDomain `json:"domains,omitempty"`
 } // @name UserAccess
 
+// AccessDomain is the data visible to the user within the context, as partner IDs, distributor IDs and markets.
+type AccessDomain struct {
+	Partners     []string `json:"partners,omitempty"`
+	Distributors []string `json:"distributors,omitempty"`
+	Markets      []string `json:"markets,omitempty"`
+} // @name AccessDomain
+
+type ClientUserAccess struct {
+	Client   Client       `json:"client"`
+	Accesses []UserAccess `json:"accesses"`
+} // @name ClientUserAccess
+
 type Context struct {
 	ID   string `json:"id,omitempty"`
 	Type string `json:"type,omitempty"`
 	Tag  string `json:"tag,omitempty"`
+	// Inherited is set on contexts derived from the distributor of an NSC context, InheritedFrom is the NSC ID.
+	Inherited     bool   `json:"inherited,omitempty"`
+	InheritedFrom string `json:"inherited_from,omitempty"`
 } // @name Context
+
+// Decision is the outcome of a check, the reason is one of partner, distributor or market when the check is allowed and
+// permission_group_not_granted or target_not_covered when it is denied.
+type Decision struct {
+	Allowed bool     `json:"allowed"`
+	Reason  string   `json:"reason"`
+	Context *Context `json:"context,omitempty"`
+} // @name Decision
+
+// Token is a signed JWT carrying the access of the user for the scopes of the calling client, verified with the keys
+// of /.well-known/jwks.json.
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+} // @name Token
+
+// UserAccessBatchResult is a line of the NDJSON stream of a batch access request, it holds either the accesses of the
+// user or the error its evaluation failed with.
+type UserAccessBatchResult struct {
+	CDSID    string       `json:"cdsid"`
+	Accesses []UserAccess `json:"accesses,omitempty"`
+	// Stale is set when the user data is served from the cache past its TTL.
+	Stale bool   `json:"stale,omitempty"`
+	Error *Error `json:"error,omitempty"`
+} // @name UserAccessBatchResult
+
+// ConfigChange is the data of the config.revision and config.reset events, the summary of a revision of the IAM
+// configuration. Reset events only carry the current revision and digest.
+type ConfigChange struct {
+	Revision uint64 `json:"revision"`
+	// Digest is the hash of the configuration, the same configuration has the same digest on every instance.
+	Digest string `json:"digest"`
+	// Scopes are keyed by scope key and Roles by role ID.
+	Scopes ChangeSet `json:"scopes"`
+	Roles  ChangeSet `json:"roles"`
+	// Mappings are keyed by scope key and role ID, e.g. user-admin/<role ID>.
+	Mappings ChangeSet `json:"mappings"`
+	// PermissionGroups are keyed by scope key and permission group key, e.g. user-admin/view_user_details.
+	PermissionGroups ChangeSet `json:"permission_groups"`
+} // @name ConfigChange
+
+type ChangeSet struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+} // @name ChangeSet
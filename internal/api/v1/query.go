@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+const (
+	cdsidParam           = "cdsid"
+	emailParam           = "email"
+	userIDParam          = "user_id"
+	providerParam        = "provider"
+	providerUserIDParam  = "provider_user_id"
+	contextParam         = "context"
+	primaryParam         = "primary"
+	scopeParam           = "scope"
+	permissionGroupParam = "permission_group"
+	partnerParam         = "partner"
+	partnerTypeParam     = "partner_type"
+	distributorParam     = "distributor"
+	marketParam          = "market"
+)
+
+var (
+	errInvalidLookup = errors.New("exactly one of cdsid, email, user_id or provider and provider_user_id is required")
+	errInvalidTarget = errors.New("exactly one of partner, distributor or market is required")
+)
+
+// lookupFromQuery reads the user lookup of the query form of the user endpoints, exactly one user key must be set.
+func lookupFromQuery(query url.Values) (plums.Lookup, error) {
+	var lookups []plums.Lookup
+	if cdsid := query.Get(cdsidParam); cdsid != "" {
+		lookups = append(lookups, plums.ByCDSID(cdsid))
+	}
+
+	if email := query.Get(emailParam); email != "" {
+		lookups = append(lookups, plums.ByEmail(email))
+	}
+
+	if userID := query.Get(userIDParam); userID != "" {
+		lookups = append(lookups, plums.ByUserID(userID))
+	}
+
+	provider, providerUserID := query.Get(providerParam), query.Get(providerUserIDParam)
+	if provider != "" || providerUserID != "" {
+		if provider == "" || providerUserID == "" {
+			return plums.Lookup{}, errInvalidLookup
+		}
+		lookups = append(lookups, plums.ByIdentity(provider, providerUserID))
+	}
+
+	if len(lookups) != 1 {
+		return plums.Lookup{}, errInvalidLookup
+	}
+
+	if !lookups[0].Valid() {
+		return plums.Lookup{}, fmt.Errorf("%w: values must not contain /, \\ or ..", plums.ErrInvalidLookup)
+	}
+
+	return lookups[0], nil
+}
+
+// accessOptions reads the partner context selection of the user access endpoints.
+func accessOptions(query url.Values) ([]authz.AccessOption, error) {
+	var opts []authz.AccessOption
+	if contextID := query.Get(contextParam); contextID != "" {
+		opts = append(opts, authz.WithContext(contextID))
+	}
+
+	if raw := query.Get(primaryParam); raw != "" {
+		primary, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("field primary is invalid")
+		}
+
+		if primary {
+			opts = append(opts, authz.WithPrimaryContext())
+		}
+	}
+
+	return opts, nil
+}
+
+// targetFromQuery reads the target of the check endpoints, exactly one of partner, distributor or market must be set.
+func targetFromQuery(query url.Values) (authz.Target, error) {
+	var targets []authz.Target
+	if partner := query.Get(partnerParam); partner != "" {
+		targets = append(targets, authz.Target{Domain: store.DomainPartner, ID: partner, PartnerType: query.Get(partnerTypeParam)})
+	}
+
+	if distributor := query.Get(distributorParam); distributor != "" {
+		targets = append(targets, authz.Target{Domain: store.DomainDistributor, ID: distributor})
+	}
+
+	if market := query.Get(marketParam); market != "" {
+		targets = append(targets, authz.Target{Domain: store.DomainMarket, ID: market})
+	}
+
+	if len(targets) != 1 {
+		return authz.Target{}, errInvalidTarget
+	}
+
+	return targets[0], nil
+}
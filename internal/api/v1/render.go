@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+	"github.com/volvo-cars/go-render"
+)
+
+// staleHeader tells consumers that the user data may be outdated.
+const staleHeader = "X-Data-Stale"
+
+// ndjsonContentType is the content type of streamed responses, one JSON object per line.
+const ndjsonContentType = "application/x-ndjson"
+
+const (
+	defaultBatchConcurrency = 8
+	defaultBatchMaxUsers    = 5000
+	// batchUserBytes is the body size allowed per user of a batch, batchBaseBytes covers the scopes and the rest.
+	batchUserBytes = 256
+	batchBaseBytes = 64 << 10
+)
+
+// success renders data in the standard envelope, the metadata is only included when it is set.
+func success(w http.ResponseWriter, status int, data any, meta Meta) {
+	if meta == (Meta{}) {
+		render.Success(w, status, data)
+		return
+	}
+
+	writeJSON(w, status, Response[any]{Data: data, Meta: &meta})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// userMeta returns the response metadata of a user and flags stale user data through the header.
+func userMeta(w http.ResponseWriter, user authz.User) Meta {
+	if user.Stale {
+		w.Header().Set(staleHeader, "true")
+	}
+
+	return Meta{Stale: user.Stale}
+}
@@ -0,0 +1,14 @@
+package v1
+
+type TokenRequest struct {
+	// CDSID selects the user of the token, only privileged clients may request tokens of other users than the subject
+	// of their bearer token.
+	CDSID string `json:"cdsid,omitempty"`
+	// Scopes default to all dependant scopes of the calling client.
+	Scopes []string `json:"scopes,omitempty"`
+} // @name TokenRequest
+
+type UserAccessBatchRequest struct {
+	CDSIDs []string `json:"cdsids"`
+	Scopes []string `json:"scopes"`
+} // @name UserAccessBatchRequest
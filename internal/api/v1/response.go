@@ -1,9 +1,25 @@
 package v1
 
 type Response[T any] struct {
-	Data T `json:"data"`
+	Data T     `json:"data"`
+	Meta *Meta `json:"meta,omitempty"`
 } // @name Response
 
+type Meta struct {
+	// Stale is set when the user data is served from the cache past its TTL, e.g. while PLUMS is unavailable.
+	Stale bool `json:"stale,omitempty"`
+	// Page is set on lists, it describes the returned part of the list.
+	Page *Page `json:"page,omitempty"`
+} // @name Meta
+
+// Page is the part of a list that was returned, Total counts the items matching the filters of the request.
+type Page struct {
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	// Limit is the requested page size, the whole list from Offset on is returned without a limit.
+	Limit int `json:"limit,omitempty"`
+} // @name Page
+
 type ErrorResponse struct {
 	Error Error `json:"error"`
 } // @name ErrorResponse
@@ -11,17 +27,22 @@ type ErrorResponse struct {
 type Error struct {
 	Code    int    `json:"code,omitempty"`
 	Message string `json:"message,omitempty"`
+	// Reason is a stable, machine readable error code, e.g. upstream_timeout.
+	Reason string `json:"reason,omitempty"`
 } // @name Error
 
 type (
-	ClientResponse       = Response[Client]        // @name ClientResponse
-	ClientsResponse      = Response[[]Client]      // @name ClientsResponse
-	RoleResponse         = Response[Role]          // @name RoleResponse
-	RolesResponse        = Response[[]Role]        // @name RolesResponse
-	ScopeResponse        = Response[Scope]         // @name ScopeResponse
-	ScopesResponse       = Response[[]Scope]       // @name ScopesResponse
-	RoleMappingResponse  = Response[RoleMapping]   // @name RoleMappingResponse
-	RoleMappingsResponse = Response[[]RoleMapping] // @name RoleMappingsResponse
-	UserResponse         = Response[User]          // @name UserResponse
-	UserAccessResponse   = Response[UserAccess]    // @name UserAccessResponse
+	ClientResponse           = Response[Client]           // @name ClientResponse
+	ClientsResponse          = Response[[]Client]         // @name ClientsResponse
+	RoleResponse             = Response[Role]             // @name RoleResponse
+	RolesResponse            = Response[[]Role]           // @name RolesResponse
+	ScopeResponse            = Response[Scope]            // @name ScopeResponse
+	ScopesResponse           = Response[[]Scope]          // @name ScopesResponse
+	RoleMappingResponse      = Response[RoleMapping]      // @name RoleMappingResponse
+	RoleMappingsResponse     = Response[[]RoleMapping]    // @name RoleMappingsResponse
+	UserResponse             = Response[User]             // @name UserResponse
+	UserAccessResponse       = Response[UserAccess]       // @name UserAccessResponse
+	ClientUserAccessResponse = Response[ClientUserAccess] // @name ClientUserAccessResponse
+	DecisionResponse         = Response[Decision]         // @name DecisionResponse
+	TokenResponse            = Response[Token]            // @name TokenResponse
 )
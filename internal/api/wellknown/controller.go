@@ -0,0 +1,38 @@
+package wellknown
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/auth"
+)
+
+// jwksMaxAge lets consumers cache the key set, keys are published before they sign so that rotations stay unnoticed.
+const jwksMaxAge = "public, max-age=300"
+
+type keySet interface {
+	JWKS() auth.JWKS
+}
+
+type Controller struct {
+	keys keySet
+}
+
+func NewController(keys keySet) *Controller {
+	return &Controller{keys: keys}
+}
+
+func (c *Controller) RegisterRoutes(router chi.Router) {
+	router.Route("/.well-known", func(r chi.Router) {
+		r.Get("/jwks.json", c.getJWKS)
+	})
+}
+
+// getJWKS serves the public keys the tokens of POST /v1/iam/token are verified with.
+func (c *Controller) getJWKS(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("Cache-Control", jwksMaxAge)
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(c.keys.JWKS())
+}
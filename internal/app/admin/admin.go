@@ -11,13 +11,26 @@ import (
 	"github.com/volvo-cars/connect-access-control/internal/config"
 )
 
-func Run(cfg *config.Config) {
+// Option adds routes to the admin server.
+type Option func(mux *http.ServeMux)
+
+// WithHandler serves the handler at the pattern of the admin server, next to the probes and metrics.
+func WithHandler(pattern string, handler http.Handler) Option {
+	return func(mux *http.ServeMux) {
+		mux.Handle(pattern, handler)
+	}
+}
+
+func Run(cfg *config.Config, opts ...Option) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/livez", OK)
 	mux.HandleFunc("/readyz", OK)
 	mux.Handle("/metrics", promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
 		EnableOpenMetrics: true,
 	}))
+	for _, opt := range opts {
+		opt(mux)
+	}
 
 	const readTimeout = 10 * time.Second
 	const writeTimeout = 10 * time.Second
@@ -3,30 +3,55 @@ package authz
 import (
 	"context"
 	"log/slog"
+	"net"
 	"os"
 	"os/signal"
+	"runtime/debug"
 	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 	"github.com/volvo-cars/connect-access-control/internal/api"
+	"github.com/volvo-cars/connect-access-control/internal/api/admin"
+	extauthzapi "github.com/volvo-cars/connect-access-control/internal/api/extauthz"
+	"github.com/volvo-cars/connect-access-control/internal/api/rpc"
+	"github.com/volvo-cars/connect-access-control/internal/api/rpc/accesscontrolv1"
 	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+	"github.com/volvo-cars/connect-access-control/internal/api/wellknown"
+	adminapp "github.com/volvo-cars/connect-access-control/internal/app/admin"
 	"github.com/volvo-cars/connect-access-control/internal/config"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/auth"
 	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
-	cachemanager "github.com/volvo-cars/connect-access-control/internal/pkg/gateway/cache-manager"
-	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/cors"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/events"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/extauthz"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/identity"
 	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/token"
 	httpserver "github.com/volvo-cars/go-ecp-httpserver"
 	"github.com/volvo-cars/go-middlewares"
 	"github.com/volvo-cars/go-observer"
 	"github.com/volvo-cars/go-tracer/otel"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 // @title			Access Control API
 // @version		1.0
 // @description	Access Control API for the Volvo Cars Connect platform.
 // @BasePath		/v1
+//
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				Bearer token of the caller, required when AUTH_ENABLED is set
 func Run(cfg *config.Config) {
 	// Set up OpenTelemetry :: create tracer
 	tp, err := NewTracerProvider(context.Background(), cfg)
@@ -47,22 +72,108 @@ func Run(cfg *config.Config) {
 		return
 	}
 
-	plumsCfg, err := plums.LoadConfig()
+	broker := events.NewBroker(store, cfg.Events.BufferSize)
+
+	identityCfg, err := identity.LoadConfig()
+	if err != nil {
+		slog.Error("failed to load identity config", slog.Any("error", err))
+		return
+	}
+
+	sources, err := newIdentitySources(identityCfg, observer.NewOutgoingCollector(cfg.App.Name))
+	if err != nil {
+		slog.Error("failed to set up identity sources", slog.Any("error", err))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if sources.plums != nil {
+		go sources.plums.Persist(ctx)
+	}
+
+	if cfg.IAM.ReloadInterval > 0 {
+		go store.Run(ctx, cfg.IAM.ReloadInterval, broker.Publish)
+	}
+
+	identityProviders, err := authz.LoadIdentityProviders(identityCfg.ProvidersFile)
+	if err != nil {
+		slog.Error("failed to load identity providers", slog.Any("error", err))
+		return
+	}
+
+	authClient := authz.NewService(sources.partners, sources.users, store, authz.WithIdentityProviders(identityProviders))
+
+	authCfg, err := auth.LoadConfig()
+	if err != nil {
+		slog.Error("failed to load auth config", slog.Any("error", err))
+		return
+	}
+
+	var (
+		authenticator  *auth.Authenticator
+		controllerOpts = []v1.ControllerOption{
+			v1.WithBatchLimits(cfg.Batch.Concurrency, cfg.Batch.MaxUsers),
+			v1.WithEvents(broker, cfg.Events.HeartbeatInterval),
+		}
+	)
+	if authCfg.Enabled {
+		keys, err := auth.NewKeySet(ctx, authCfg)
+		if err != nil {
+			slog.Error("failed to load JWKS", slog.Any("error", err))
+			return
+		}
+		go keys.Run(ctx)
+
+		authenticator = auth.New(authCfg, keys, auth.NewDenialCollector(prometheus.DefaultRegisterer))
+		controllerOpts = append(controllerOpts, v1.WithAuthenticator(authenticator))
+	}
+
+	tokenCfg, err := token.LoadConfig()
 	if err != nil {
-		slog.Error("failed to load plums config", slog.Any("error", err))
+		slog.Error("failed to load token config", slog.Any("error", err))
 		return
 	}
 
-	cacheManagerCfg, err := cachemanager.LoadConfig()
+	var signer *token.Signer
+	if tokenCfg.Enabled {
+		if authenticator == nil {
+			slog.Error("tokens require authentication, set AUTH_ENABLED")
+			return
+		}
+
+		signer, err = token.NewSigner(tokenCfg)
+		if err != nil {
+			slog.Error("failed to load token signing keys", slog.Any("error", err))
+			return
+		}
+		go signer.Run(ctx)
+
+		controllerOpts = append(controllerOpts, v1.WithTokenSigner(signer))
+	}
+
+	extAuthzCfg, err := extauthz.LoadConfig()
 	if err != nil {
-		slog.Error("failed to load cache-manager config", slog.Any("error", err))
+		slog.Error("failed to load ext_authz config", slog.Any("error", err))
 		return
 	}
 
-	outgoingCollector := observer.NewOutgoingCollector(cfg.App.Name)
-	plumsClient := plums.New(plumsCfg, outgoingCollector)
-	cacheManagerClient := cachemanager.New(cacheManagerCfg, outgoingCollector)
-	authClient := authz.NewService(cacheManagerClient, plumsClient, store)
+	if extAuthzCfg.Enabled && authenticator == nil {
+		slog.Error("ext_authz requires authentication, set AUTH_ENABLED")
+		return
+	}
+
+	corsCfg, err := cors.LoadConfig()
+	if err != nil {
+		slog.Error("failed to load cors config", slog.Any("error", err))
+		return
+	}
+
+	var corsPolicy *cors.Policy
+	if corsCfg.Enabled {
+		corsPolicy = cors.New(corsCfg, store)
+	}
 
 	// main router
 	r := chi.NewRouter()
@@ -74,10 +185,45 @@ func Run(cfg *config.Config) {
 
 	// controllers
 	controllers := []api.Controller{
-		v1.NewController(store, authClient),
+		v1.NewController(store, authClient, controllerOpts...),
+	}
+
+	r.Mount("/v1", api.RegisterRoutes(NewAPIRouter(cfg, corsPolicy, authenticator), controllers...))
+	if signer != nil {
+		api.RegisterRoutes(r, wellknown.NewController(signer))
+	}
+	if extAuthzCfg.Enabled {
+		authorizer := extauthz.New(extAuthzCfg, store, authenticator, authClient)
+		r.Mount(extAuthzCfg.PathPrefix, api.RegisterRoutes(NewAPIRouter(cfg, nil, nil), extauthzapi.NewController(extAuthzCfg.PathPrefix, authorizer, authenticator)))
 	}
 
-	r.Mount("/v1", api.RegisterRoutes(NewAPIRouter(cfg), controllers...))
+	// admin server, the cache endpoints are kept off the API port
+	var adminOpts []adminapp.Option
+	if sources.partnerCache != nil {
+		adminRouter := chi.NewRouter()
+		adminRouter.Mount("/admin", api.RegisterRoutes(NewAdminRouter(), admin.NewController(sources.partnerCache)))
+		adminOpts = append(adminOpts, adminapp.WithHandler("/admin/", adminRouter))
+	}
+	adminapp.Run(cfg, adminOpts...)
+
+	// grpc server
+	rpcOpts := []rpc.ServerOption{rpc.WithBatchLimits(cfg.Batch.Concurrency, cfg.Batch.MaxChecks)}
+	if authenticator != nil {
+		rpcOpts = append(rpcOpts, rpc.WithAuthenticator(authenticator))
+	}
+
+	grpcServer, healthServer := NewGRPCServer(rpc.NewServer(store, authClient, rpcOpts...))
+	listener, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+	if err != nil {
+		slog.Error("grpc server failed to listen", slog.Any("port", cfg.GRPC.Port), slog.Any("error", err))
+		return
+	}
+
+	grpcNotify := make(chan error, 1)
+	go func() {
+		grpcNotify <- grpcServer.Serve(listener)
+	}()
+	slog.Info("grpc server listening", slog.Any("port", cfg.GRPC.Port))
 
 	// http server
 	httpServer := httpserver.New(r, httpserver.Port(cfg.HTTP.Port))
@@ -91,33 +237,117 @@ func Run(cfg *config.Config) {
 	select {
 	case err := <-httpServer.Notify():
 		slog.Error("http server failed to start serve", slog.Any("error", slog.Any("error", err)))
+	case err := <-grpcNotify:
+		slog.Error("grpc server failed to serve", slog.Any("error", err))
 	case sig := <-exit:
 		slog.Info("http server received termination signal", slog.Any("signal", sig))
 	}
 
+	healthServer.Shutdown()
+	stopGRPCServer(grpcServer, cfg.GRPC.ShutdownTimeout)
+	// event streams never end on their own, the http server would wait for them until its shutdown timeout
+	broker.Close()
+
 	if err = httpServer.Shutdown(); err != nil {
 		slog.Error("http server failed to shutdown", slog.Any("error", slog.Any("error", err)))
 		return
 	}
 
+	if sources.plums != nil {
+		if err = sources.plums.Save(); err != nil {
+			slog.Error("failed to persist plums user cache", slog.Any("error", err))
+		}
+	}
+
 	slog.Info("http server shutdown successfully")
 }
 
-func NewAPIRouter(cfg *config.Config) *chi.Mux {
+// NewAPIRouter returns the router of the API, cross-origin requests are answered when a CORS policy is given and
+// requests are authenticated when an authenticator is given.
+func NewAPIRouter(cfg *config.Config, corsPolicy *cors.Policy, authenticator *auth.Authenticator) *chi.Mux {
 	observerMiddleware := observer.New(observer.NewMetricCollector(cfg.App.Name))
 
 	router := chi.NewRouter()
 
+	// the client certificate header is only trusted from the proxies, by the address of the connection before RealIP
+	if authenticator != nil {
+		router.Use(auth.Peer)
+	}
 	// These middlewares (RealIP, RequestId, CorrelationId) should be inserted fairly early in the middleware stack to
 	// ensure that subsequent layers (e.g., request loggers)
 	router.Use(middleware.RealIP)
 	router.Use(middlewares.RequestId)
 	router.Use(middlewares.CorrelationId)
-	router.Use(middleware.NoCache)
+	// responses are not stored unless the endpoint answers conditional requests and sets its own cache policy
+	router.Use(middleware.SetHeader("Cache-Control", "no-store"))
 	router.Use(otel.Handler)
 	router.Use(observerMiddleware.Handler)
 	router.Use(middlewares.RequestLogger())
 	router.Use(middleware.Recoverer)
+	// preflight requests carry no credentials, so the CORS policy answers them before authentication
+	if corsPolicy != nil {
+		router.Use(corsPolicy.Middleware)
+	}
+	if authenticator != nil {
+		router.Use(authenticator.Middleware)
+	}
+	return router
+}
+
+// NewGRPCServer returns the gRPC server of the access control service and the standard health service, which reports
+// serving until it is shut down.
+func NewGRPCServer(server *rpc.Server) (*grpc.Server, *health.Server) {
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(recoverer, server.UnaryInterceptor),
+	)
+	server.Register(grpcServer)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(accesscontrolv1.AccessControlService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	return grpcServer, healthServer
+}
+
+// recoverer turns a panic of a call into an internal error, as middleware.Recoverer does for HTTP requests.
+func recoverer(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.ErrorContext(ctx, "grpc call panicked", slog.String("method", info.FullMethod), slog.Any("panic", rec), slog.String("stack", string(debug.Stack())))
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// stopGRPCServer lets in-flight calls finish, calls still running after the timeout are cancelled.
+func stopGRPCServer(server *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		slog.Warn("grpc server did not stop in time, cancelling in-flight calls")
+		server.Stop()
+	}
+}
+
+func NewAdminRouter() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Use(middleware.RealIP)
+	router.Use(middlewares.RequestId)
+	router.Use(middlewares.CorrelationId)
+	router.Use(middleware.NoCache)
+	router.Use(otel.Handler)
+	router.Use(middlewares.RequestLogger())
+	router.Use(middleware.Recoverer)
 	return router
 }
 
@@ -0,0 +1,89 @@
+package authz
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	cachemanager "github.com/volvo-cars/connect-access-control/internal/pkg/gateway/cache-manager"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/identity"
+)
+
+type outgoingCollector interface {
+	ObserveRequestTimeWithOp(dependency, operation, method, route string, status int, duration time.Duration)
+}
+
+// identitySources are the user and partner sources of the authz service, in the order of IDENTITY_SOURCES.
+type identitySources struct {
+	users    identity.UserSource
+	partners identity.PartnerSource
+
+	// plums and partnerCache are only set when PLUMS is one of the sources.
+	plums        *plums.CachedGateway
+	partnerCache *cachemanager.CachedGateway
+}
+
+func newIdentitySources(cfg *identity.Config, collector outgoingCollector) (*identitySources, error) {
+	sources := &identitySources{}
+
+	var (
+		users    []identity.UserSource
+		partners []identity.PartnerSource
+	)
+
+	for _, source := range cfg.Sources {
+		switch source {
+		case identity.SourcePlums:
+			if sources.plums != nil {
+				return nil, fmt.Errorf("identity source [%s] is configured more than once", source)
+			}
+
+			plumsCfg, err := plums.LoadConfig()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load plums config: %w", err)
+			}
+
+			cacheManagerCfg, err := cachemanager.LoadConfig()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load cache-manager config: %w", err)
+			}
+
+			sources.plums = plums.NewCachedGateway(plumsCfg, plums.New(plumsCfg, collector))
+			if err = sources.plums.Load(); err != nil {
+				slog.Warn("failed to restore plums user cache", slog.Any("error", err))
+			}
+
+			sources.partnerCache = cachemanager.NewCachedGateway(cacheManagerCfg, cachemanager.New(cacheManagerCfg, collector))
+
+			users = append(users, sources.plums)
+			partners = append(partners, sources.partnerCache)
+		case identity.SourceStatic:
+			if cfg.StaticDir == "" {
+				return nil, fmt.Errorf("identity source [%s] requires IDENTITY_STATIC_DIR", source)
+			}
+
+			static, err := identity.NewStaticSource(cfg.StaticDir)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load static identity source: %w", err)
+			}
+
+			users = append(users, static)
+			partners = append(partners, static)
+		default:
+			return nil, fmt.Errorf("unknown identity source [%s]", source)
+		}
+	}
+
+	switch len(users) {
+	case 0:
+		return nil, fmt.Errorf("no identity source is configured")
+	case 1:
+		sources.users, sources.partners = users[0], partners[0]
+	default:
+		sources.users = identity.NewCompositeUserSource(users...)
+		sources.partners = identity.NewCompositePartnerSource(partners...)
+	}
+
+	return sources, nil
+}
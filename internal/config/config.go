@@ -1,15 +1,20 @@
 package config
 
 import (
+	"time"
+
 	"github.com/caarlos0/env/v11"
 )
 
 type Config struct {
 	App    App
 	HTTP   HTTP
+	GRPC   GRPC
 	Log    Log
 	Tracer Tracer
 	IAM    IAM
+	Batch  Batch
+	Events Events
 }
 
 type App struct {
@@ -23,6 +28,28 @@ type HTTP struct {
 	AdminPort string `env:"HTTP_ADMIN_PORT" envDefault:"8081"`
 }
 
+type GRPC struct {
+	Port string `env:"GRPC_PORT" envDefault:"9090"`
+	// ShutdownTimeout bounds the graceful stop, in-flight calls are cancelled afterwards.
+	ShutdownTimeout time.Duration `env:"GRPC_SHUTDOWN_TIMEOUT" envDefault:"10s"`
+}
+
+type Batch struct {
+	// Concurrency bounds the users of a batch access request that are evaluated at once.
+	Concurrency int `env:"BATCH_CONCURRENCY" envDefault:"8"`
+	// MaxUsers rejects batch access requests with more users.
+	MaxUsers int `env:"BATCH_MAX_USERS" envDefault:"5000"`
+	// MaxChecks rejects gRPC batch checks with more checks, which are decided Concurrency at a time.
+	MaxChecks int `env:"BATCH_MAX_CHECKS" envDefault:"1000"`
+}
+
+type Events struct {
+	// BufferSize is the number of events kept for consumers that resume the event stream.
+	BufferSize int `env:"EVENTS_BUFFER_SIZE" envDefault:"64"`
+	// HeartbeatInterval keeps idle event streams open through proxies.
+	HeartbeatInterval time.Duration `env:"EVENTS_HEARTBEAT_INTERVAL" envDefault:"30s"`
+}
+
 type Log struct {
 	Level string `env:"LOG_LEVEL" envDefault:"info"`
 }
@@ -33,6 +60,9 @@ type Tracer struct {
 
 type IAM struct {
 	RootDir string `env:"IAM_ROOT_DIR,required"`
+	// ReloadInterval is how often the configuration is read again, changes are published on the event stream. Reloading
+	// is disabled with 0.
+	ReloadInterval time.Duration `env:"IAM_RELOAD_INTERVAL" envDefault:"0"`
 }
 
 func (c *Config) IsLocal() bool {
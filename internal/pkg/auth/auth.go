@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/reasons"
+	"github.com/volvo-cars/go-render"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrMissingToken     = errors.New("bearer token is missing")
+	ErrInvalidToken     = errors.New("bearer token is invalid")
+	ErrInvalidAudience  = errors.New("token audience is not accepted")
+	ErrMissingSubject   = errors.New("token has no subject")
+	ErrMissingClient    = errors.New("token has no client")
+	ErrNotAuthenticated = errors.New("request is not authenticated")
+	ErrNotPrivileged    = errors.New("client is not allowed to query other users")
+	ErrUnknownClient    = errors.New("client is not registered")
+)
+
+// signingMethods are the accepted token algorithms, symmetric algorithms are never accepted.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+type denialCollector interface {
+	ObserveDenied(clientID, reason string)
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject is the value of the subject claim, the user the me endpoints are served for. Callers authenticated by
+	// a client certificate have no subject.
+	Subject string
+	// ClientID is the value of the client claim or the common name of the client certificate, the application that called.
+	ClientID string
+	Claims   jwt.MapClaims
+}
+
+type principalKey struct{}
+
+func NewContext(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal of an authenticated request.
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// Authenticator validates the bearer tokens of requests against the configured issuer, audience and key set.
+type Authenticator struct {
+	cfg       *Config
+	keys      *KeySet
+	parser    *jwt.Parser
+	collector denialCollector
+}
+
+func New(cfg *Config, keys *KeySet, collector denialCollector) *Authenticator {
+	return &Authenticator{
+		cfg:       cfg,
+		keys:      keys,
+		collector: collector,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(signingMethods),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithLeeway(cfg.Leeway),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+		),
+	}
+}
+
+// Authenticate validates the token and returns its principal.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	audience, err := claims.GetAudience()
+	if err != nil || !slices.ContainsFunc(audience, func(aud string) bool { return slices.Contains(a.cfg.Audience, aud) }) {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, ErrInvalidAudience)
+	}
+
+	subject, _ := claims[a.cfg.SubjectClaim].(string)
+	clientID, _ := claims[a.cfg.ClientClaim].(string)
+
+	return Principal{
+		Subject:  subject,
+		ClientID: clientID,
+		Claims:   claims,
+	}, nil
+}
+
+// Middleware authenticates the bearer token or, without a token, the client certificate of the request and adds the
+// principal to the request context. Requests without either are only passed on anonymously when authentication is
+// not required, an invalid token is always rejected.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			if clientID, ok := certificateClientID(r, a.cfg); ok {
+				trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("auth.client_id", clientID))
+				next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), Principal{ClientID: clientID})))
+				return
+			}
+
+			if a.cfg.Required {
+				a.unauthorized(w, ErrMissingToken)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, err := a.Authenticate(r.Context(), token)
+		if err != nil {
+			trace.SpanFromContext(r.Context()).RecordError(err)
+			a.unauthorized(w, err)
+			return
+		}
+
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("auth.client_id", principal.ClientID))
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), principal)))
+	})
+}
+
+// Required reports whether requests without credentials are rejected.
+func (a *Authenticator) Required() bool {
+	return a.cfg.Required
+}
+
+// Lookup returns the user lookup of the subject of the principal.
+func (a *Authenticator) Lookup(principal Principal) (plums.Lookup, error) {
+	if principal.Subject == "" {
+		return plums.Lookup{}, fmt.Errorf("%w: claim [%s]", ErrMissingSubject, a.cfg.SubjectClaim)
+	}
+
+	switch plums.LookupKind(a.cfg.SubjectLookup) {
+	case plums.LookupEmail:
+		return plums.ByEmail(principal.Subject), nil
+	case plums.LookupUserID:
+		return plums.ByUserID(principal.Subject), nil
+	case plums.LookupIdentity:
+		return plums.ByIdentity(a.cfg.SubjectProvider, principal.Subject), nil
+	default:
+		return plums.ByCDSID(principal.Subject), nil
+	}
+}
+
+// Owns reports whether the lookup is the user of the subject of the principal.
+func (a *Authenticator) Owns(principal Principal, lookup plums.Lookup) bool {
+	own, err := a.Lookup(principal)
+	if err != nil || own.Kind != lookup.Kind || own.Provider != lookup.Provider {
+		return false
+	}
+
+	return strings.EqualFold(own.Value, lookup.Value)
+}
+
+// Privileged reports whether the client of the principal may query any user.
+func (a *Authenticator) Privileged(principal Principal) bool {
+	return principal.ClientID != "" && slices.Contains(a.cfg.PrivilegedClients, principal.ClientID)
+}
+
+// RestrictUserRoutes reports whether only privileged clients may query users other than themselves.
+func (a *Authenticator) RestrictUserRoutes() bool {
+	return a.cfg.RestrictUserRoutes
+}
+
+// EnforceClientScopes reports whether authenticated clients are limited to their dependant scopes.
+func (a *Authenticator) EnforceClientScopes() bool {
+	return a.cfg.EnforceClientScopes
+}
+
+// ObserveDenied counts a call of the client that was denied for the reason.
+func (a *Authenticator) ObserveDenied(clientID, reason string) {
+	if a.collector != nil {
+		a.collector.ObserveDenied(clientID, reason)
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
+
+// unauthorized rejects a request without valid credentials, the caller is unknown and counted as anonymous.
+func (a *Authenticator) unauthorized(w http.ResponseWriter, err error) {
+	a.ObserveDenied("", reasons.Unauthenticated)
+
+	challenge := "Bearer"
+	if !errors.Is(err, ErrMissingToken) {
+		challenge = `Bearer error="invalid_token"`
+	}
+
+	w.Header().Set("WWW-Authenticate", challenge)
+	render.Failure(w, http.StatusUnauthorized, err)
+}
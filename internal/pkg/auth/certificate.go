@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+type peerKey struct{}
+
+// Peer remembers the address of the connection a request came in on. Install it before middleware.RealIP, which
+// replaces the remote address of the request with the client address forwarded in its headers.
+func Peer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), peerKey{}, r.RemoteAddr)))
+	})
+}
+
+// trustedProxy reports whether the request came in on a connection from one of the trusted proxies.
+func trustedProxy(r *http.Request, proxies []netip.Prefix) bool {
+	remoteAddr, ok := r.Context().Value(peerKey{}).(string)
+	if !ok {
+		remoteAddr = r.RemoteAddr
+	}
+
+	addrPort, err := netip.ParseAddrPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+
+	addr := addrPort.Addr().Unmap()
+	return slices.ContainsFunc(proxies, func(prefix netip.Prefix) bool { return prefix.Contains(addr) })
+}
+
+// certificateClientID returns the client ID of a verified client certificate, the common name of the certificate
+// subject. Certificates are read from the TLS connection or, behind a TLS terminating proxy, from the header named by
+// cfg.ClientCertHeader, in the X-Forwarded-Client-Cert format of Envoy. The header is only trusted when it is
+// configured and the request came in from one of the trusted proxies.
+func certificateClientID(r *http.Request, cfg *Config) (string, bool) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		if cn := r.TLS.VerifiedChains[0][0].Subject.CommonName; cn != "" {
+			return cn, true
+		}
+	}
+
+	header := cfg.ClientCertHeader
+	if header == "" || !trustedProxy(r, cfg.trustedProxies) {
+		return "", false
+	}
+
+	value := r.Header.Get(header)
+	if value == "" {
+		return "", false
+	}
+
+	// the first element is the certificate of the original client, proxies append their own
+	element := splitQuoted(value, ',')[0]
+	for _, field := range splitQuoted(element, ';') {
+		key, val, ok := strings.Cut(field, "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "Subject") {
+			continue
+		}
+
+		for _, attribute := range splitQuoted(strings.Trim(val, `"`), ',') {
+			name, cn, ok := strings.Cut(strings.TrimSpace(attribute), "=")
+			if ok && strings.EqualFold(name, "CN") && cn != "" {
+				return cn, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// splitQuoted splits s at sep outside of double quotes.
+func splitQuoted(s string, sep rune) []string {
+	var (
+		parts  []string
+		quoted bool
+		start  int
+	)
+
+	for i, c := range s {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
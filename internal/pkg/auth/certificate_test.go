@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificateClientID(t *testing.T) {
+	const header = "X-Forwarded-Client-Cert"
+	cfg := &Config{
+		ClientCertHeader: header,
+		trustedProxies:   []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("::1/128")},
+	}
+
+	tests := map[string]struct {
+		remoteAddr string
+		xfcc       string
+		clientID   string
+	}{
+		"subject of the client": {
+			remoteAddr: "10.1.2.3:443",
+			xfcc:       `Hash=abc;Subject="CN=client-a,OU=connect,O=Volvo Cars";URI=spiffe://client-a`,
+			clientID:   "client-a",
+		},
+		"first element of many": {
+			remoteAddr: "10.1.2.3:443",
+			xfcc:       `By=spiffe://proxy;Subject="CN=client-a,O=Volvo Cars",By=spiffe://edge;Subject="CN=proxy"`,
+			clientID:   "client-a",
+		},
+		"unquoted subject": {
+			remoteAddr: "10.1.2.3:443",
+			xfcc:       `Hash=abc;subject=CN=client-a`,
+			clientID:   "client-a",
+		},
+		"IPv4 mapped proxy address": {
+			remoteAddr: "[::ffff:10.1.2.3]:443",
+			xfcc:       `Subject="CN=client-a"`,
+			clientID:   "client-a",
+		},
+		"IPv6 proxy address": {
+			remoteAddr: "[::1]:443",
+			xfcc:       `Subject="CN=client-a"`,
+			clientID:   "client-a",
+		},
+		"untrusted proxy": {
+			remoteAddr: "192.168.1.1:443",
+			xfcc:       `Subject="CN=client-a"`,
+		},
+		"invalid remote address": {
+			remoteAddr: "proxy",
+			xfcc:       `Subject="CN=client-a"`,
+		},
+		"without subject": {
+			remoteAddr: "10.1.2.3:443",
+			xfcc:       `Hash=abc;URI=spiffe://client-a`,
+		},
+		"without common name": {
+			remoteAddr: "10.1.2.3:443",
+			xfcc:       `Subject="OU=connect,O=Volvo Cars"`,
+		},
+		"empty common name": {
+			remoteAddr: "10.1.2.3:443",
+			xfcc:       `Subject="CN=,O=Volvo Cars"`,
+		},
+		"without header": {
+			remoteAddr: "10.1.2.3:443",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.xfcc != "" {
+				r.Header.Set(header, tt.xfcc)
+			}
+
+			clientID, ok := certificateClientID(r, cfg)
+			assert.Equal(t, tt.clientID != "", ok)
+			assert.Equal(t, tt.clientID, clientID)
+		})
+	}
+}
+
+func TestCertificateClientIDUsesPeerAddress(t *testing.T) {
+	cfg := &Config{
+		ClientCertHeader: "X-Forwarded-Client-Cert",
+		trustedProxies:   []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	}
+
+	// middleware.RealIP replaced the remote address with the forwarded client address
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "192.168.1.1:443"
+	r.Header.Set("X-Forwarded-Client-Cert", `Subject="CN=client-a"`)
+
+	_, ok := certificateClientID(r, cfg)
+	assert.False(t, ok)
+
+	r = r.WithContext(context.WithValue(r.Context(), peerKey{}, "10.1.2.3:443"))
+	clientID, ok := certificateClientID(r, cfg)
+	assert.True(t, ok)
+	assert.Equal(t, "client-a", clientID)
+}
+
+func TestCertificateClientIDFromTLS(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "client-a"}}}},
+	}
+
+	// verified certificates of the connection are trusted without a header
+	clientID, ok := certificateClientID(r, &Config{})
+	assert.True(t, ok)
+	assert.Equal(t, "client-a", clientID)
+}
+
+func TestParsePrefix(t *testing.T) {
+	tests := map[string]struct {
+		prefix string
+		valid  bool
+	}{
+		"10.0.0.0/8":    {prefix: "10.0.0.0/8", valid: true},
+		"10.1.2.3":      {prefix: "10.1.2.3/32", valid: true},
+		" 10.1.2.3 ":    {prefix: "10.1.2.3/32", valid: true},
+		"::1":           {prefix: "::1/128", valid: true},
+		"10.0.0.0/33":   {},
+		"proxy.example": {},
+	}
+
+	for s, tt := range tests {
+		t.Run(s, func(t *testing.T) {
+			prefix, err := parsePrefix(s)
+			if !tt.valid {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.prefix, prefix.String())
+		})
+	}
+}
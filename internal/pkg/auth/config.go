@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	env "github.com/caarlos0/env/v11"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+)
+
+type Config struct {
+	// Enabled validates the bearer token of API requests, the me endpoints are only served when it is set.
+	Enabled bool `env:"AUTH_ENABLED" envDefault:"false"`
+	// Required rejects requests without a bearer token, unset it to roll authentication out to existing callers.
+	Required bool   `env:"AUTH_REQUIRED" envDefault:"true"`
+	Issuer   string `env:"AUTH_ISSUER"`
+	// Audience lists the accepted audiences, a token must carry one of them.
+	Audience []string      `env:"AUTH_AUDIENCE"`
+	Leeway   time.Duration `env:"AUTH_LEEWAY" envDefault:"30s"`
+
+	// JWKSURL is polled for the signing keys, JWKSFile reads them once from a local file instead, e.g. in tests.
+	JWKSURL             string        `env:"AUTH_JWKS_URL"`
+	JWKSFile            string        `env:"AUTH_JWKS_FILE"`
+	JWKSRefreshInterval time.Duration `env:"AUTH_JWKS_REFRESH_INTERVAL" envDefault:"15m"`
+
+	// SubjectClaim holds the user of the me endpoints, it is looked up as SubjectLookup, one of cdsid, email, user_id
+	// or identity. Identities are looked up at SubjectProvider, e.g. the oid claim of Azure AD tokens.
+	SubjectClaim    string `env:"AUTH_SUBJECT_CLAIM" envDefault:"sub"`
+	SubjectLookup   string `env:"AUTH_SUBJECT_LOOKUP" envDefault:"cdsid"`
+	SubjectProvider string `env:"AUTH_SUBJECT_PROVIDER" envDefault:"AzureAD_VCC"`
+
+	// ClientClaim holds the ID of the calling client. With RestrictUserRoutes only the PrivilegedClients may query
+	// the access of any user, all other callers are limited to the me endpoints.
+	ClientClaim        string   `env:"AUTH_CLIENT_CLAIM" envDefault:"azp"`
+	PrivilegedClients  []string `env:"AUTH_PRIVILEGED_CLIENTS"`
+	RestrictUserRoutes bool     `env:"AUTH_RESTRICT_USER_ROUTES" envDefault:"false"`
+
+	// ClientCertHeader names the header a TLS terminating proxy forwards verified client certificates in, clients
+	// without a token are then identified by the common name of their certificate. It is not trusted when empty, and
+	// only on connections from the ClientCertTrustedProxies, CIDRs or addresses of the proxies, which are required
+	// with it.
+	ClientCertHeader         string   `env:"AUTH_CLIENT_CERT_HEADER"`
+	ClientCertTrustedProxies []string `env:"AUTH_CLIENT_CERT_TRUSTED_PROXIES"`
+	// EnforceClientScopes limits every authenticated client to the dependant scopes of its client.yaml.
+	EnforceClientScopes bool `env:"AUTH_ENFORCE_CLIENT_SCOPES" envDefault:"true"`
+
+	trustedProxies []netip.Prefix
+}
+
+func LoadConfig() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	if !cfg.Enabled {
+		return cfg, nil
+	}
+
+	if cfg.Issuer == "" {
+		return nil, errors.New("AUTH_ISSUER is required when authentication is enabled")
+	}
+
+	if len(cfg.Audience) == 0 {
+		return nil, errors.New("AUTH_AUDIENCE is required when authentication is enabled")
+	}
+
+	if cfg.JWKSURL == "" && cfg.JWKSFile == "" {
+		return nil, errors.New("one of AUTH_JWKS_URL or AUTH_JWKS_FILE is required when authentication is enabled")
+	}
+
+	if cfg.ClientCertHeader != "" && len(cfg.ClientCertTrustedProxies) == 0 {
+		return nil, errors.New("AUTH_CLIENT_CERT_TRUSTED_PROXIES is required with AUTH_CLIENT_CERT_HEADER")
+	}
+
+	for _, proxy := range cfg.ClientCertTrustedProxies {
+		prefix, err := parsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("AUTH_CLIENT_CERT_TRUSTED_PROXIES [%s] is not a CIDR or address: %w", proxy, err)
+		}
+		cfg.trustedProxies = append(cfg.trustedProxies, prefix)
+	}
+
+	switch plums.LookupKind(cfg.SubjectLookup) {
+	case plums.LookupCDSID, plums.LookupEmail, plums.LookupUserID, plums.LookupIdentity:
+	default:
+		return nil, fmt.Errorf("AUTH_SUBJECT_LOOKUP [%s] is not one of cdsid, email, user_id or identity", cfg.SubjectLookup)
+	}
+
+	return cfg, nil
+}
+
+// parsePrefix parses a CIDR, a single address is a prefix of its own.
+func parsePrefix(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		return netip.ParsePrefix(s)
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrUnsupportedKey = errors.New("unsupported key")
+
+// JWKS is a JSON Web Key Set, as served by identity providers.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a public RSA or EC JSON Web Key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// RSA keys
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC keys
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// NewJWK returns the signing JWK of an RSA or EC public key.
+func NewJWK(kid, alg string, key crypto.PublicKey) (JWK, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: alg,
+			N:   encodeBase64(k.N.Bytes()),
+			E:   encodeBase64(big.NewInt(int64(k.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return JWK{
+			Kty: "EC",
+			Kid: kid,
+			Use: "sig",
+			Alg: alg,
+			Crv: k.Curve.Params().Name,
+			X:   encodeBase64(k.X.FillBytes(make([]byte, size))),
+			Y:   encodeBase64(k.Y.FillBytes(make([]byte, size))),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("%w: %T", ErrUnsupportedKey, key)
+	}
+}
+
+// PublicKey decodes the public key of the JWK.
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus of key [%s]: %w", k.Kid, err)
+		}
+
+		e, err := decodeBase64(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent of key [%s]: %w", k.Kid, err)
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		curve, err := curve(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+
+		x, err := decodeBase64(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate of key [%s]: %w", k.Kid, err)
+		}
+
+		y, err := decodeBase64(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate of key [%s]: %w", k.Kid, err)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("%w: key type [%s]", ErrUnsupportedKey, k.Kty)
+	}
+}
+
+// Thumbprint returns the RFC 7638 SHA-256 thumbprint of the key, base64url encoded, a stable key ID of the key.
+func (k JWK) Thumbprint() (string, error) {
+	var members string
+	switch k.Kty {
+	case "RSA":
+		members = fmt.Sprintf(`{"e":%q,"kty":%q,"n":%q}`, k.E, k.Kty, k.N)
+	case "EC":
+		members = fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q,"y":%q}`, k.Crv, k.Kty, k.X, k.Y)
+	default:
+		return "", fmt.Errorf("%w: key type [%s]", ErrUnsupportedKey, k.Kty)
+	}
+
+	sum := sha256.Sum256([]byte(members))
+	return encodeBase64(sum[:]), nil
+}
+
+func curve(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("%w: curve [%s]", ErrUnsupportedKey, name)
+	}
+}
+
+func encodeBase64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
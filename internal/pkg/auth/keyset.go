@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	jwksTimeout = 10 * time.Second
+	// minRefreshInterval limits the refreshes triggered by tokens signed with an unknown key.
+	minRefreshInterval = time.Minute
+)
+
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// KeySet holds the public keys tokens are verified with, read from a local JWKS file or fetched from a JWKS URL.
+type KeySet struct {
+	cfg    *Config
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	refreshedAt time.Time
+}
+
+func NewKeySet(ctx context.Context, cfg *Config) (*KeySet, error) {
+	k := &KeySet{
+		cfg:    cfg,
+		client: &http.Client{Timeout: jwksTimeout},
+	}
+
+	if err := k.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// Key returns the key of the key ID, a key set served from a URL is refreshed once for an unknown key ID, so that
+// rotated keys are picked up before the next scheduled refresh. A token without key ID is verified with the only key.
+func (k *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := k.get(kid); ok {
+		return key, nil
+	}
+
+	k.mu.RLock()
+	stale := time.Since(k.refreshedAt) >= minRefreshInterval
+	k.mu.RUnlock()
+
+	if k.cfg.JWKSURL == "" || !stale {
+		return nil, fmt.Errorf("%w: [%s]", ErrKeyNotFound, kid)
+	}
+
+	if err := k.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	if key, ok := k.get(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: [%s]", ErrKeyNotFound, kid)
+}
+
+func (k *KeySet) get(kid string) (crypto.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// Refresh reads the key set again, keys that cannot be decoded are skipped.
+func (k *KeySet) Refresh(ctx context.Context) error {
+	jwks, err := k.read(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.PublicKey()
+		if err != nil {
+			slog.WarnContext(ctx, "skipping JWKS key", slog.String("kid", jwk.Kid), slog.Any("error", err))
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.refreshedAt = time.Now()
+	k.mu.Unlock()
+
+	return nil
+}
+
+// Run periodically refreshes a key set served from a URL until ctx is done.
+func (k *KeySet) Run(ctx context.Context) {
+	if k.cfg.JWKSURL == "" {
+		return
+	}
+
+	ticker := time.NewTicker(k.cfg.JWKSRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.Refresh(ctx); err != nil {
+				slog.Error("failed to refresh JWKS", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func (k *KeySet) read(ctx context.Context) (JWKS, error) {
+	var jwks JWKS
+	if k.cfg.JWKSFile != "" {
+		data, err := os.ReadFile(k.cfg.JWKSFile)
+		if err != nil {
+			return jwks, fmt.Errorf("failed to read JWKS file [%s]: %w", k.cfg.JWKSFile, err)
+		}
+
+		if err := json.Unmarshal(data, &jwks); err != nil {
+			return jwks, fmt.Errorf("failed to unmarshal JWKS file [%s]: %w", k.cfg.JWKSFile, err)
+		}
+
+		return jwks, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.cfg.JWKSURL, nil)
+	if err != nil {
+		return jwks, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+
+	res, err := k.client.Do(req)
+	if err != nil {
+		return jwks, fmt.Errorf("failed to fetch JWKS [%s]: %w", k.cfg.JWKSURL, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return jwks, fmt.Errorf("failed to fetch JWKS [%s]: status %d", k.cfg.JWKSURL, res.StatusCode)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return jwks, fmt.Errorf("failed to read JWKS [%s]: %w", k.cfg.JWKSURL, err)
+	}
+
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return jwks, fmt.Errorf("failed to unmarshal JWKS [%s]: %w", k.cfg.JWKSURL, err)
+	}
+
+	return jwks, nil
+}
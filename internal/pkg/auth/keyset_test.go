@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jwksServer serves the keys of the key IDs and counts the requests.
+type jwksServer struct {
+	kids     atomic.Value
+	requests atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, kids ...string) (*jwksServer, string) {
+	t.Helper()
+
+	s := &jwksServer{}
+	s.kids.Store(kids)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.requests.Add(1)
+
+		jwks := JWKS{}
+		for _, kid := range s.kids.Load().([]string) {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			require.NoError(t, err)
+
+			jwk, err := NewJWK(kid, "ES256", key.Public())
+			require.NoError(t, err)
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	t.Cleanup(server.Close)
+
+	return s, server.URL
+}
+
+func TestKeySetKey(t *testing.T) {
+	tests := map[string]struct {
+		kids []string
+		kid  string
+		err  error
+	}{
+		"known key":                  {kids: []string{"a", "b"}, kid: "b"},
+		"unknown key":                {kids: []string{"a", "b"}, kid: "c", err: ErrKeyNotFound},
+		"without key ID, single key": {kids: []string{"a"}, kid: ""},
+		"without key ID, many keys":  {kids: []string{"a", "b"}, kid: "", err: ErrKeyNotFound},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, url := newJWKSServer(t, tt.kids...)
+			keys, err := NewKeySet(context.Background(), &Config{JWKSURL: url})
+			require.NoError(t, err)
+
+			key, err := keys.Key(context.Background(), tt.kid)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, key)
+		})
+	}
+}
+
+func TestKeySetRefreshRateLimit(t *testing.T) {
+	server, url := newJWKSServer(t, "a")
+	keys, err := NewKeySet(context.Background(), &Config{JWKSURL: url})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, server.requests.Load())
+
+	// a rotated key is not fetched again right after the last refresh
+	server.kids.Store([]string{"a", "b"})
+	for range 3 {
+		_, err = keys.Key(context.Background(), "b")
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	}
+	assert.EqualValues(t, 1, server.requests.Load())
+
+	// once the minimum refresh interval passed, an unknown key triggers a single refresh
+	keys.mu.Lock()
+	keys.refreshedAt = time.Now().Add(-minRefreshInterval)
+	keys.mu.Unlock()
+
+	key, err := keys.Key(context.Background(), "b")
+	assert.NoError(t, err)
+	assert.NotNil(t, key)
+
+	_, err = keys.Key(context.Background(), "c")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	assert.EqualValues(t, 2, server.requests.Load())
+}
+
+func TestKeySetFileIsNotRefreshed(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwk, err := NewJWK("a", "ES256", key.Public())
+	require.NoError(t, err)
+
+	unsupported := JWK{Kty: "oct", Kid: "b"}
+	encryption := jwk
+	encryption.Kid, encryption.Use = "c", "enc"
+
+	data, err := json.Marshal(JWKS{Keys: []JWK{jwk, unsupported, encryption}})
+	require.NoError(t, err)
+
+	file := t.TempDir() + "/jwks.json"
+	require.NoError(t, os.WriteFile(file, data, 0o600))
+
+	keys, err := NewKeySet(context.Background(), &Config{JWKSFile: file})
+	require.NoError(t, err)
+
+	// keys that cannot be decoded and keys not used for signatures are skipped
+	assert.Len(t, keys.keys, 1)
+
+	keys.refreshedAt = time.Time{}
+	_, err = keys.Key(context.Background(), "b")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// anonymousClient labels the denials of callers without a client ID.
+const anonymousClient = "anonymous"
+
+// DenialCollector counts the API calls denied by caller authorization, per client and reason.
+type DenialCollector struct {
+	denied *prometheus.CounterVec
+}
+
+// NewDenialCollector registers the denial counter, a counter registered before is reused.
+func NewDenialCollector(registerer prometheus.Registerer) *DenialCollector {
+	denied := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "access_control_denied_requests_total",
+		Help: "API requests denied by caller authorization, by client and reason.",
+	}, []string{"client", "reason"})
+
+	if err := registerer.Register(denied); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			denied = registered.ExistingCollector.(*prometheus.CounterVec)
+		}
+	}
+
+	return &DenialCollector{denied: denied}
+}
+
+// ObserveDenied counts a denied call, calls without a client ID are counted as anonymous.
+func (c *DenialCollector) ObserveDenied(clientID, reason string) {
+	if clientID == "" {
+		clientID = anonymousClient
+	}
+
+	c.denied.WithLabelValues(clientID, reason).Inc()
+}
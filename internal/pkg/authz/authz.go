@@ -4,113 +4,248 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
 
 	cachemanager "github.com/volvo-cars/connect-access-control/internal/pkg/gateway/cache-manager"
 	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
 	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //go:generate mockgen -source=authz.go -destination=mocks/authz_mock.go -package=mocks
 
-const (
-	azureIdentityProvider = "AzureAD_VCC"
-	cdsIDDelimiter        = "@"
-)
+const azureIdentityProvider = "AzureAD_VCC"
 
-var ErrUserNotFound = errors.New("user not found")
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrClientNotFound  = errors.New("client not found")
+	ErrScopeNotAllowed = errors.New("scope is not a dependant scope of the client")
+)
 
 //go:generate
 type cacheClient interface {
 	GetPartnersByCodes(ctx context.Context, partnerCodes []string, partnerType string) ([]*cachemanager.Partner, error)
+	GetPartnersByDistributor(ctx context.Context, distributorID string, partnerType string) ([]*cachemanager.Partner, error)
 }
 
 //go:generate
 type plumsClient interface {
-	GetUserByCDSID(ctx context.Context, cdsid string) (*plums.User, error)
+	GetUser(ctx context.Context, lookup plums.Lookup) (*plums.User, error)
 }
 
 //go:generate
 type authzStore interface {
+	GetClient(key string) (store.Client, error)
+	GetScope(key string) (store.Scope, error)
 	GetRoleMapping(scopeID, roleID string) (store.RoleMapping, error)
 	GetRoleMappings(scopeID string) ([]store.RoleMapping, error)
 }
 
 type Service struct {
-	cache      cacheClient
-	plums      plumsClient
-	authzStore authzStore
+	cache             cacheClient
+	plums             plumsClient
+	authzStore        authzStore
+	identityProviders []IdentityProvider
 }
 
-func NewService(cache cacheClient, plums plumsClient, authzStore authzStore) *Service {
-	return &Service{
-		cache:      cache,
-		plums:      plums,
-		authzStore: authzStore,
+type ServiceOption func(*Service)
+
+// WithIdentityProviders sets the identity providers the CDSID of a user is extracted from.
+func WithIdentityProviders(providers []IdentityProvider) ServiceOption {
+	return func(s *Service) {
+		s.identityProviders = providers
 	}
 }
 
-func (s *Service) GetUserAccess(ctx context.Context, cdsid string, scopes []string) ([]UserAccess, error) {
-	user, err := s.GetUserByCDSID(ctx, cdsid)
+func NewService(cache cacheClient, plums plumsClient, authzStore authzStore, opts ...ServiceOption) *Service {
+	s := &Service{
+		cache:             cache,
+		plums:             plums,
+		authzStore:        authzStore,
+		identityProviders: DefaultIdentityProviders(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// GetUserAccess evaluates the access of the user in every partner context, or only in the contexts selected by opts.
+// Mappings that inherit add a context for every partner under the distributor of an NSC context, these inherited
+// contexts are only evaluated without a context selection.
+func (s *Service) GetUserAccess(ctx context.Context, lookup plums.Lookup, scopes []string, opts ...AccessOption) (Access, error) {
+	var options accessOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	plumsUser, err := s.getPlumsUser(ctx, lookup)
 	if err != nil {
-		return nil, fmt.Errorf("GetUserAccess error: %w", err)
+		return Access{}, fmt.Errorf("GetUserAccess error: %w", err)
+	}
+
+	if options.selective() {
+		selected := *plumsUser
+		selected.Partners = options.selectPlumsPartners(plumsUser.Partners)
+		if len(selected.Partners) == 0 {
+			return Access{}, ErrContextNotFound
+		}
+		plumsUser = &selected
+	}
+
+	user, err := s.buildUserInfo(ctx, plumsUser)
+	if err != nil {
+		return Access{}, fmt.Errorf("GetUserAccess error: %w", err)
+	}
+
+	if options.selective() {
+		user.Partners = options.selectPartners(user.Partners)
+		if len(user.Partners) == 0 {
+			return Access{}, ErrContextNotFound
+		}
 	}
 
 	userType := detectUserType(user)
 
-	var accesses []UserAccess
+	var (
+		accesses     []UserAccess
+		inheritances []inheritance
+	)
 	for _, partner := range user.Partners {
 		// Evaluate role mappings
-		permissionGroups, err := s.evaluateRoleAccess(partner, scopes, userType)
+		evaluated, err := s.evaluateRoleAccess(partner, scopes, userType)
 		if err != nil {
-			return nil, fmt.Errorf("failed to evaluate role mappings error: %w", err)
+			return Access{}, fmt.Errorf("failed to evaluate role mappings error: %w", err)
+		}
+
+		if len(evaluated.permissionGroups) > 0 || len(evaluated.domains) > 0 {
+			accesses = append(accesses, UserAccess{
+				Context: Context{
+					ID:   partner.ID,
+					Type: partner.Type,
+					Tag:  partner.ParmaPartnerCode,
+				},
+				Roles:            partner.Roles,
+				PermissionGroups: evaluated.permissionGroups,
+				Domains:          evaluated.domains,
+			})
 		}
 
-		if len(permissionGroups) == 0 {
+		if len(evaluated.inherited) == 0 || options.selective() {
 			continue
 		}
 
-		accesses = append(accesses, UserAccess{
-			Context: Context{
-				ID:   partner.ID,
-				Type: partner.Type,
-				Tag:  partner.ParmaPartnerCode,
-			},
-			Roles:            partner.Roles,
-			PermissionGroups: permissionGroups,
-		})
+		inheritances = append(inheritances, inheritance{nsc: partner, permissionGroups: evaluated.inherited})
+	}
+
+	// inherited contexts never repeat an own partner of the user, whether or not it has access of its own
+	own := make(map[string]struct{}, len(user.Partners))
+	for _, partner := range user.Partners {
+		own[partner.ID] = struct{}{}
 	}
 
-	return accesses, nil
+	for _, from := range inheritances {
+		inherited, err := s.inheritAccess(ctx, from, own)
+		if err != nil {
+			return Access{}, fmt.Errorf("failed to evaluate inherited access error: %w", err)
+		}
+		accesses = append(accesses, inherited...)
+	}
+
+	return Access{
+		User:     user,
+		Accesses: accesses,
+	}, nil
 }
 
-func (s *Service) GetUserByCDSID(ctx context.Context, cdsid string) (User, error) {
-	user, err := s.plums.GetUserByCDSID(ctx, cdsid)
+// GetClientUserAccess evaluates the access of the user for a client, scopes default to the dependant scopes of the
+// client and any other scope is rejected with ErrScopeNotAllowed.
+func (s *Service) GetClientUserAccess(ctx context.Context, clientID string, lookup plums.Lookup, scopes []string, opts ...AccessOption) (ClientAccess, error) {
+	client, err := s.authzStore.GetClient(clientID)
 	if err != nil {
-		if errors.Is(err, plums.ErrUserNotFound) {
-			return User{}, ErrUserNotFound
+		if errors.Is(err, store.ErrClientNotFound) {
+			return ClientAccess{}, fmt.Errorf("%w: [%s]", ErrClientNotFound, clientID)
 		}
-		return User{}, fmt.Errorf("GetUser error: %w", err)
+		return ClientAccess{}, fmt.Errorf("GetClientUserAccess error: %w", err)
+	}
+
+	if len(scopes) == 0 {
+		scopes = client.DependantScopes
+	}
+
+	for _, scope := range scopes {
+		if !contains(client.DependantScopes, scope) {
+			return ClientAccess{}, fmt.Errorf("%w: [%s]", ErrScopeNotAllowed, scope)
+		}
+	}
+
+	access, err := s.GetUserAccess(ctx, lookup, scopes, opts...)
+	if err != nil {
+		return ClientAccess{}, err
+	}
+
+	return ClientAccess{
+		Client: client,
+		Access: access,
+	}, nil
+}
+
+// GetUser resolves a user by CDSID, email, PLUMS user ID or identity provider user ID.
+func (s *Service) GetUser(ctx context.Context, lookup plums.Lookup) (User, error) {
+	user, err := s.getPlumsUser(ctx, lookup)
+	if err != nil {
+		return User{}, err
 	}
 
 	return s.buildUserInfo(ctx, user)
 }
 
+func (s *Service) getPlumsUser(ctx context.Context, lookup plums.Lookup) (*plums.User, error) {
+	user, err := s.plums.GetUser(ctx, lookup)
+	if err != nil {
+		if errors.Is(err, plums.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("GetUser error: %w", err)
+	}
+
+	return user, nil
+}
+
 func (s *Service) buildUserInfo(ctx context.Context, plumsUser *plums.User) (User, error) {
-	cdsid := getCdsIDFromUserIdentities(plumsUser.UserIdentities)
+	cdsid := resolveCDSID(s.identityProviders, plumsUser.UserIdentities)
+	if cdsid == "" {
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("cdsid.unresolved", true))
+		slog.WarnContext(ctx, "user CDSID could not be resolved from the user identities", slog.String("userId", plumsUser.UserID), slog.Any("providers", providerNames(plumsUser.UserIdentities)))
+	}
 
 	partners, err := s.buildPartners(ctx, plumsUser.Partners)
 	if err != nil {
 		return User{}, fmt.Errorf("failed to build partners error: %w", err)
 	}
 
+	unresolved := unresolvedPartners(plumsUser.Partners, partners)
+	if len(unresolved) > 0 {
+		slog.WarnContext(ctx, "user partners could not be resolved", slog.String("userId", plumsUser.UserID), slog.Any("partnerIds", unresolved))
+	}
+
 	return User{
-		ID:          plumsUser.UserID,
-		Email:       plumsUser.Email,
-		CDSID:       cdsid,
-		CountryCode: plumsUser.CountryCode,
-		Partners:    partners,
+		ID:                 plumsUser.UserID,
+		FirstName:          plumsUser.FirstName,
+		LastName:           plumsUser.LastName,
+		Email:              plumsUser.Email,
+		CDSID:              cdsid,
+		CDSIDUnresolved:    cdsid == "",
+		CountryCode:        plumsUser.CountryCode,
+		Partners:           partners,
+		Identities:         toIdentities(plumsUser.UserIdentities),
+		UnresolvedPartners: unresolved,
+		Stale:              plumsUser.Stale,
 	}, nil
 }
 
@@ -161,6 +296,7 @@ func (s *Service) buildPartners(ctx context.Context, partners []plums.Partner) (
 					Active:           cachedPartner.Active,
 					IsPrimary:        pp.IsPrimary,
 					Roles:            pp.Roles,
+					Address:          toAddress(cachedPartner.Address),
 				}
 			}
 		}(partnerType, partnerIds)
@@ -187,17 +323,37 @@ func (s *Service) buildPartners(ctx context.Context, partners []plums.Partner) (
 	return result, nil
 }
 
-func (s *Service) evaluateRoleAccess(partner Partner, scopes []string, userType string) (map[string][]string, error) {
-	permissionGroups := make(map[string][]string)
-	for _, roleID := range partner.Roles {
-		for _, scope := range scopes {
+// roleAccess is the access the role mappings grant a partner context.
+type roleAccess struct {
+	permissionGroups map[string][]string
+	domains          map[string]AccessDomain
+	// inherited are the permission groups of the inheriting mappings of an NSC context.
+	inherited map[string][]string
+}
+
+// evaluateRoleAccess returns the permission groups of the functionality scopes and the access domains of the data scopes
+// the partner roles are mapped to, together with the permission groups an NSC partner passes on to its distributor.
+func (s *Service) evaluateRoleAccess(partner Partner, scopes []string, userType string) (roleAccess, error) {
+	access := roleAccess{
+		permissionGroups: make(map[string][]string),
+		domains:          make(map[string]AccessDomain),
+		inherited:        make(map[string][]string),
+	}
+
+	for _, scope := range scopes {
+		scopeType, err := s.scopeType(scope)
+		if err != nil {
+			return roleAccess{}, err
+		}
+
+		for _, roleID := range partner.Roles {
 			roleMapping, err := s.authzStore.GetRoleMapping(scope, roleID)
 			if err != nil {
 				if errors.Is(err, store.ErrRoleMappingNotFound) {
 					continue
 				}
 
-				return nil, fmt.Errorf("GetRoleMapping error: %w", err)
+				return roleAccess{}, fmt.Errorf("GetRoleMapping error: %w", err)
 			}
 
 			for _, mapping := range roleMapping.Mapping {
@@ -213,14 +369,37 @@ func (s *Service) evaluateRoleAccess(partner Partner, scopes []string, userType
 					continue
 				}
 
+				if scopeType == store.ScopeTypeData {
+					access.domains[scope] = access.domains[scope].grant(partner, mapping.Domains)
+					continue
+				}
+
 				// TODO: Q: should only show matched roleID(s) in the response?
 				// Append permission groups
-				permissionGroups[scope] = append(permissionGroups[scope], mapping.PermissionGroups...)
+				access.permissionGroups[scope] = append(access.permissionGroups[scope], mapping.PermissionGroups...)
+
+				if mapping.Inherit && partner.Type == store.PartnerTypeNsc.String() {
+					access.inherited[scope] = append(access.inherited[scope], mapping.PermissionGroups...)
+				}
 			}
 		}
 	}
 
-	return permissionGroups, nil
+	return access, nil
+}
+
+// scopeType returns the type of a scope, unknown scopes have no role mappings and are treated as functionality scopes.
+func (s *Service) scopeType(key string) (store.ScopeType, error) {
+	scope, err := s.authzStore.GetScope(key)
+	if err != nil {
+		if errors.Is(err, store.ErrScopeNotFound) {
+			return store.ScopeTypeFunctionality, nil
+		}
+
+		return "", fmt.Errorf("GetScope error: %w", err)
+	}
+
+	return scope.Type, nil
 }
 
 func getPartners(partners map[string]plums.Partner, partnerType string, cachedPartner *cachemanager.Partner) plums.Partner {
@@ -238,20 +417,48 @@ func getPartners(partners map[string]plums.Partner, partnerType string, cachedPa
 	return plums.Partner{}
 }
 
-func getCdsIDFromUserIdentities(identities []plums.UserIdentity) string {
-	const minParts = 2
-	for _, identity := range identities {
-		if identity.Provider == azureIdentityProvider {
-			parts := strings.Split(identity.AccountName, cdsIDDelimiter)
+// unresolvedPartners returns the IDs of the PLUMS partners without a matching cache-manager partner.
+func unresolvedPartners(plumsPartners []plums.Partner, partners []Partner) []string {
+	resolved := make(map[string]struct{}, len(partners)*2)
+	for _, partner := range partners {
+		resolved[partner.ID] = struct{}{}
+		resolved[partner.ParmaPartnerCode] = struct{}{}
+	}
 
-			if len(parts) < minParts {
-				return ""
-			}
-			return parts[0]
+	var unresolved []string
+	for _, partner := range plumsPartners {
+		if _, ok := resolved[partner.PartnerID]; !ok {
+			unresolved = append(unresolved, partner.PartnerID)
 		}
 	}
 
-	return ""
+	return unresolved
+}
+
+func toAddress(address cachemanager.Address) Address {
+	return Address{
+		CountryName:  address.CountryName,
+		AddressLine1: address.AddressLine1,
+		AddressLine2: address.AddressLine2,
+		AddressLine3: address.AddressLine3,
+		AddressLine4: address.AddressLine4,
+		City:         address.City,
+		District:     address.District,
+		State:        address.State,
+		PostalCode:   address.PostalCode,
+		CountryCode:  address.CountryCode,
+		LanguageCode: address.LanguageCode,
+	}
+}
+
+// providerNames returns the providers of the user identities, to report which identities did not yield a CDSID.
+func providerNames(identities []plums.UserIdentity) []string {
+	names := make([]string, len(identities))
+	for i, identity := range identities {
+		names[i] = identity.Provider
+	}
+
+	return names
 }
 
 func detectUserType(user User) string {
@@ -0,0 +1,229 @@
+package authz
+
+import (
+	"context"
+	"sync"
+
+	cachemanager "github.com/volvo-cars/connect-access-control/internal/pkg/gateway/cache-manager"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+)
+
+// BatchResult is the access of a user of a batch, or the error its evaluation failed with.
+type BatchResult struct {
+	// Index is the position of the user in the batch.
+	Index  int
+	Lookup plums.Lookup
+	Access Access
+	Err    error
+}
+
+// GetUsersAccess evaluates the access of the users of a batch with at most concurrency users in flight. Partners and
+// the partners under a distributor are only looked up once for all users of the batch. The results are passed to
+// yield one at a time in the order they complete, users that were not started before ctx is done are skipped.
+func (s *Service) GetUsersAccess(ctx context.Context, lookups []plums.Lookup, scopes []string, concurrency int, yield func(BatchResult)) {
+	batch := *s
+	batch.cache = newBatchPartners(s.cache)
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make(chan BatchResult)
+	go func() {
+		defer close(results)
+
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, concurrency)
+
+	loop:
+		for i, lookup := range lookups {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				break loop
+			}
+
+			wg.Add(1)
+			go func(i int, lookup plums.Lookup) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				access, err := batch.GetUserAccess(ctx, lookup, scopes)
+				results <- BatchResult{Index: i, Lookup: lookup, Access: access, Err: err}
+			}(i, lookup)
+		}
+
+		wg.Wait()
+	}()
+
+	for result := range results {
+		yield(result)
+	}
+}
+
+// CheckRequest is a check of a batch.
+type CheckRequest struct {
+	Lookup          plums.Lookup
+	Scope           string
+	PermissionGroup string
+	Target          Target
+}
+
+// CheckResult is the decision of a check of a batch, or the error it failed with.
+type CheckResult struct {
+	Decision Decision
+	Err      error
+}
+
+// BatchCheck decides the checks of a batch with at most concurrency checks in flight, the results are in the order of
+// the checks. The access of a user is evaluated once per scope and partners are only looked up once for all checks
+// of the batch, checks that were not started before ctx is done fail with its error.
+func (s *Service) BatchCheck(ctx context.Context, checks []CheckRequest, concurrency int) []CheckResult {
+	batch := *s
+	batch.cache = newBatchPartners(s.cache)
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu       sync.Mutex
+		accesses = make(map[string]*batchAccess)
+	)
+	access := func(lookup plums.Lookup, scope string) *batchAccess {
+		mu.Lock()
+		defer mu.Unlock()
+
+		key := lookup.Key() + "|" + scope
+		if accesses[key] == nil {
+			accesses[key] = &batchAccess{}
+		}
+
+		return accesses[key]
+	}
+
+	results := make([]CheckResult, len(checks))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, check := range checks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, check CheckRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			memo := access(check.Lookup, check.Scope)
+			decision, err := batch.check(ctx, check.Scope, check.PermissionGroup, check.Target, func() (Access, error) {
+				memo.once.Do(func() {
+					memo.access, memo.err = batch.GetUserAccess(ctx, check.Lookup, []string{check.Scope})
+				})
+				return memo.access, memo.err
+			})
+			results[i] = CheckResult{Decision: decision, Err: err}
+		}(i, check)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// batchAccess is the access of a user for a scope, evaluated once for all checks of a batch.
+type batchAccess struct {
+	once   sync.Once
+	access Access
+	err    error
+}
+
+// batchPartners remembers the partner lookups of a batch in front of the cache client, partners that cache-manager
+// does not know about are remembered as well.
+type batchPartners struct {
+	cache cacheClient
+
+	mu           sync.Mutex
+	partners     map[string]*cachemanager.Partner
+	distributors map[string][]*cachemanager.Partner
+}
+
+func newBatchPartners(cache cacheClient) *batchPartners {
+	return &batchPartners{
+		cache:        cache,
+		partners:     make(map[string]*cachemanager.Partner),
+		distributors: make(map[string][]*cachemanager.Partner),
+	}
+}
+
+func (b *batchPartners) GetPartnersByCodes(ctx context.Context, partnerCodes []string, partnerType string) ([]*cachemanager.Partner, error) {
+	partners := make([]*cachemanager.Partner, 0, len(partnerCodes))
+	var missing []string
+
+	b.mu.Lock()
+	for _, code := range partnerCodes {
+		partner, ok := b.partners[batchKey(partnerType, code)]
+		if !ok {
+			missing = append(missing, code)
+			continue
+		}
+
+		if partner != nil {
+			partners = append(partners, partner)
+		}
+	}
+	b.mu.Unlock()
+
+	if len(missing) == 0 {
+		return partners, nil
+	}
+
+	fetched, err := b.cache.GetPartnersByCodes(ctx, missing, partnerType)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, partner := range fetched {
+		b.partners[batchKey(partnerType, cachemanager.PartnerCode(partner, partnerType))] = partner
+	}
+
+	for _, code := range cachemanager.MissingCodes(missing, fetched, partnerType) {
+		b.partners[batchKey(partnerType, code)] = nil
+	}
+
+	return append(partners, fetched...), nil
+}
+
+func (b *batchPartners) GetPartnersByDistributor(ctx context.Context, distributorID string, partnerType string) ([]*cachemanager.Partner, error) {
+	key := batchKey(partnerType, distributorID)
+
+	b.mu.Lock()
+	partners, ok := b.distributors[key]
+	b.mu.Unlock()
+
+	if ok {
+		return partners, nil
+	}
+
+	partners, err := b.cache.GetPartnersByDistributor(ctx, distributorID, partnerType)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.distributors[key] = partners
+	b.mu.Unlock()
+
+	return partners, nil
+}
+
+func batchKey(partnerType, code string) string {
+	return partnerType + ":" + code
+}
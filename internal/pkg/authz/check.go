@@ -0,0 +1,226 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cachemanager "github.com/volvo-cars/connect-access-control/internal/pkg/gateway/cache-manager"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+var (
+	ErrInvalidTarget  = errors.New("target must be a partner, distributor or market")
+	ErrTargetNotFound = errors.New("target not found")
+	// ErrInvalidCheck is returned for a permission group of a data scope, data scopes grant access domains only.
+	ErrInvalidCheck = errors.New("permission group must be empty for data scopes")
+)
+
+// CheckReason explains a decision, the allowed reasons name the part of the partner hierarchy the target was found in.
+type CheckReason string
+
+const (
+	// ReasonPartner allows a target that is the partner of a context.
+	ReasonPartner CheckReason = "partner"
+	// ReasonDistributor allows a target under the distributor of a context, e.g. a retailer of an NSC.
+	ReasonDistributor CheckReason = "distributor"
+	// ReasonMarket allows a target within the market of a context.
+	ReasonMarket CheckReason = "market"
+	// ReasonNotGranted denies a target when no context of the user grants the permission group.
+	ReasonNotGranted CheckReason = "permission_group_not_granted"
+	// ReasonNotCovered denies a target outside of the contexts that grant the permission group.
+	ReasonNotCovered CheckReason = "target_not_covered"
+)
+
+func (r CheckReason) String() string {
+	return string(r)
+}
+
+// rank orders the allowed reasons from the most to the least specific.
+func (r CheckReason) rank() int {
+	switch r {
+	case ReasonPartner:
+		return 0
+	case ReasonDistributor:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Target is the resource a check is made against, a partner, a distributor or a market.
+type Target struct {
+	Domain store.Domain
+	ID     string
+	// PartnerType is the cache-manager type of a partner target, PARMA partners are identified by their PARMA code and
+	// any other partner by its ID. It defaults to PARMA.
+	PartnerType string
+}
+
+// Decision is the outcome of a check, Context is the user context that allowed it.
+type Decision struct {
+	Allowed bool
+	Reason  CheckReason
+	Context *Context
+}
+
+// resource is a resolved target, the partner, distributor and market it belongs to.
+type resource struct {
+	partnerID     string
+	distributorID string
+	market        string
+}
+
+// Check decides whether the user holds the permission group of the scope for the target. A context allows the
+// target when the target is the partner of the context, or when the context is an NSC and the target is under its
+// distributor or in its market, the most specific of the allowing contexts is reported. For data scopes the access
+// domains of the context decide instead, a permission group is rejected with ErrInvalidCheck then. An empty
+// permission group of a functionality scope matches any permission group.
+func (s *Service) Check(ctx context.Context, lookup plums.Lookup, scope, permissionGroup string, target Target) (Decision, error) {
+	return s.check(ctx, scope, permissionGroup, target, func() (Access, error) {
+		return s.GetUserAccess(ctx, lookup, []string{scope})
+	})
+}
+
+// check decides a check with the access of the user for the scope that getAccess evaluates.
+func (s *Service) check(ctx context.Context, scope, permissionGroup string, target Target, getAccess func() (Access, error)) (Decision, error) {
+	scopeType, err := s.scopeType(scope)
+	if err != nil {
+		return Decision{}, fmt.Errorf("Check error: %w", err)
+	}
+
+	if scopeType == store.ScopeTypeData && permissionGroup != "" {
+		return Decision{}, fmt.Errorf("%w: scope [%s]", ErrInvalidCheck, scope)
+	}
+
+	res, err := s.resolveTarget(ctx, target)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	access, err := getAccess()
+	if err != nil {
+		return Decision{}, err
+	}
+
+	partners := make(map[string]Partner, len(access.User.Partners))
+	for _, partner := range access.User.Partners {
+		partners[partner.ID] = partner
+	}
+
+	granted := false
+	decision := Decision{Reason: ReasonNotCovered}
+	for _, userAccess := range access.Accesses {
+		var domain AccessDomain
+		if scopeType == store.ScopeTypeData {
+			d, ok := userAccess.Domains[scope]
+			if !ok {
+				continue
+			}
+			domain = d
+		} else {
+			groups := userAccess.PermissionGroups[scope]
+			if len(groups) == 0 || (permissionGroup != "" && !contains(groups, permissionGroup)) {
+				continue
+			}
+			domain = contextDomain(userAccess.Context, partners[userAccess.Context.ID])
+		}
+
+		granted = true
+		reason, ok := domain.covers(res)
+		if ok && (!decision.Allowed || reason.rank() < decision.Reason.rank()) {
+			matched := userAccess.Context
+			decision = Decision{Allowed: true, Reason: reason, Context: &matched}
+		}
+	}
+
+	if !granted {
+		return Decision{Reason: ReasonNotGranted}, nil
+	}
+
+	return decision, nil
+}
+
+// resolveTarget looks the partner hierarchy of partner and distributor targets up in cache-manager.
+func (s *Service) resolveTarget(ctx context.Context, target Target) (resource, error) {
+	if target.ID == "" {
+		return resource{}, ErrInvalidTarget
+	}
+
+	switch target.Domain {
+	case store.DomainPartner:
+		partnerType := target.PartnerType
+		if partnerType == "" {
+			partnerType = store.PartnerTypeParma.String()
+		}
+
+		partner, err := s.getPartner(ctx, target.ID, partnerType)
+		if err != nil {
+			return resource{}, err
+		}
+
+		distributorID := partner.DistributorID
+		if partnerType == store.PartnerTypeNsc.String() {
+			distributorID = partner.ID
+		}
+
+		return resource{partnerID: partner.ID, distributorID: distributorID, market: partner.Market}, nil
+	case store.DomainDistributor:
+		distributor, err := s.getPartner(ctx, target.ID, store.PartnerTypeNsc.String())
+		if err != nil {
+			return resource{}, err
+		}
+
+		return resource{distributorID: distributor.ID, market: distributor.Market}, nil
+	case store.DomainMarket:
+		return resource{market: target.ID}, nil
+	default:
+		return resource{}, fmt.Errorf("%w: [%s]", ErrInvalidTarget, target.Domain)
+	}
+}
+
+func (s *Service) getPartner(ctx context.Context, code, partnerType string) (*cachemanager.Partner, error) {
+	partners, err := s.cache.GetPartnersByCodes(ctx, []string{code}, partnerType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve target error: %w", err)
+	}
+
+	for _, partner := range partners {
+		if cachemanager.PartnerCode(partner, partnerType) == code {
+			return partner, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s partner [%s]", ErrTargetNotFound, partnerType, code)
+}
+
+// contextDomain returns what a context of a functionality scope administers, its own partner and, for an NSC, the
+// partners under its distributor and in its market. An inherited context administers its partner only.
+func contextDomain(userContext Context, partner Partner) AccessDomain {
+	if userContext.Inherited {
+		return AccessDomain{Partners: appendUnique(nil, userContext.ID)}
+	}
+
+	domain := AccessDomain{Partners: appendUnique(nil, partner.ID)}
+	if partner.Type == store.PartnerTypeNsc.String() {
+		domain.Distributors = appendUnique(nil, partner.ID)
+		domain.Markets = appendUnique(nil, partner.Market)
+	}
+
+	return domain
+}
+
+// covers reports whether the resource is within the domain, with the most specific reason.
+func (d AccessDomain) covers(res resource) (CheckReason, bool) {
+	switch {
+	case res.partnerID != "" && contains(d.Partners, res.partnerID):
+		return ReasonPartner, true
+	case res.distributorID != "" && contains(d.Distributors, res.distributorID):
+		return ReasonDistributor, true
+	case res.market != "" && contains(d.Markets, res.market):
+		return ReasonMarket, true
+	default:
+		return "", false
+	}
+}
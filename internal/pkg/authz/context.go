@@ -0,0 +1,75 @@
+package authz
+
+import (
+	"errors"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+)
+
+var ErrContextNotFound = errors.New("user context not found")
+
+type accessOptions struct {
+	contextID string
+	primary   bool
+}
+
+type AccessOption func(*accessOptions)
+
+// WithContext evaluates only the partner context with the given ID, either the PLUMS partner ID
+// (the PARMA code of PARMA partners) or the cache-manager partner ID.
+func WithContext(partnerID string) AccessOption {
+	return func(o *accessOptions) {
+		o.contextID = partnerID
+	}
+}
+
+// WithPrimaryContext evaluates only the primary partner context of the user.
+func WithPrimaryContext() AccessOption {
+	return func(o *accessOptions) {
+		o.primary = true
+	}
+}
+
+func (o accessOptions) selective() bool {
+	return o.contextID != "" || o.primary
+}
+
+// selectPlumsPartners narrows the PLUMS partners down before they are resolved, so that cache-manager is only asked
+// for the selected partner. When the context ID does not match a PLUMS partner ID it may still be a cache-manager ID,
+// all partners of the selection are kept then and matched once resolved.
+func (o accessOptions) selectPlumsPartners(partners []plums.Partner) []plums.Partner {
+	var candidates []plums.Partner
+	for _, partner := range partners {
+		if o.primary && !partner.IsPrimary {
+			continue
+		}
+		candidates = append(candidates, partner)
+	}
+
+	if o.contextID == "" {
+		return candidates
+	}
+
+	for _, partner := range candidates {
+		if partner.PartnerID == o.contextID {
+			return []plums.Partner{partner}
+		}
+	}
+
+	return candidates
+}
+
+// selectPartners returns the resolved partners of the selection.
+func (o accessOptions) selectPartners(partners []Partner) []Partner {
+	if o.contextID == "" {
+		return partners
+	}
+
+	for _, partner := range partners {
+		if partner.ID == o.contextID || partner.ParmaPartnerCode == o.contextID {
+			return []Partner{partner}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package authz
+
+import (
+	"slices"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+// grant adds the domains of a matched data scope mapping for the partner, the partner domain is granted when the
+// mapping sets none. The distributor of an NSC partner is the partner itself.
+func (d AccessDomain) grant(partner Partner, domains []store.Domain) AccessDomain {
+	if len(domains) == 0 {
+		domains = []store.Domain{store.DomainPartner}
+	}
+
+	for _, domain := range domains {
+		switch domain {
+		case store.DomainPartner:
+			d.Partners = appendUnique(d.Partners, partner.ID)
+		case store.DomainDistributor:
+			distributorID := partner.DistributorID
+			if partner.Type == store.PartnerTypeNsc.String() {
+				distributorID = partner.ID
+			}
+			d.Distributors = appendUnique(d.Distributors, distributorID)
+		case store.DomainMarket:
+			d.Markets = appendUnique(d.Markets, partner.Market)
+		}
+	}
+
+	return d
+}
+
+func appendUnique(values []string, value string) []string {
+	if value == "" || slices.Contains(values, value) {
+		return values
+	}
+
+	return append(values, value)
+}
@@ -0,0 +1,151 @@
+package authz
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/utils"
+)
+
+// ExtractionRule is how the CDSID is read from an identity of a provider.
+type ExtractionRule string
+
+const (
+	// RuleAccountName takes the account name up to the delimiter, e.g. jdoe from jdoe@volvocars.com.
+	RuleAccountName ExtractionRule = "account_name"
+	// RuleRegex takes the first capture group of the pattern, matched against the account name.
+	RuleRegex ExtractionRule = "regex"
+	// RuleProviderUserID takes the provider user ID as is.
+	RuleProviderUserID ExtractionRule = "provider_user_id"
+)
+
+const defaultDelimiter = "@"
+
+// IdentityProvider is an identity provider the CDSID of a user can be extracted from,
+// providers are tried in order and the first one that yields a CDSID wins.
+type IdentityProvider struct {
+	Name      string         `json:"name"`
+	Rule      ExtractionRule `json:"rule"`
+	Delimiter string         `json:"delimiter,omitempty"`
+	Pattern   string         `json:"pattern,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+type identityProviders struct {
+	IdentityProviders []IdentityProvider `json:"identity_providers"`
+}
+
+// DefaultIdentityProviders reads the CDSID from the account name of the Azure AD identity.
+func DefaultIdentityProviders() []IdentityProvider {
+	return []IdentityProvider{
+		{Name: azureIdentityProvider, Rule: RuleAccountName, Delimiter: defaultDelimiter},
+	}
+}
+
+// LoadIdentityProviders reads the identity providers from a YAML file, the defaults are used when the file does not exist.
+func LoadIdentityProviders(filePath string) ([]IdentityProvider, error) {
+	config, err := utils.YAMLUnmarshal[identityProviders](filePath)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return DefaultIdentityProviders(), nil
+		}
+		return nil, fmt.Errorf("failed to unmarshal identity providers file [%s]: %w", filePath, err)
+	}
+
+	providers := config.IdentityProviders
+	for i := range providers {
+		if err := providers[i].compile(); err != nil {
+			return nil, fmt.Errorf("invalid identity provider [%s] in [%s]: %w", providers[i].Name, filePath, err)
+		}
+	}
+
+	return providers, nil
+}
+
+func (p *IdentityProvider) compile() error {
+	switch p.Rule {
+	case RuleAccountName:
+		if p.Delimiter == "" {
+			p.Delimiter = defaultDelimiter
+		}
+	case RuleRegex:
+		pattern, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("failed to compile pattern: %w", err)
+		}
+
+		if pattern.NumSubexp() < 1 {
+			return errors.New("pattern must have a capture group for the CDSID")
+		}
+		p.pattern = pattern
+	case RuleProviderUserID:
+	default:
+		return fmt.Errorf("unknown rule [%s]", p.Rule)
+	}
+
+	return nil
+}
+
+// extract returns the CDSID of an identity of the provider, or an empty string when the identity does not hold one.
+func (p *IdentityProvider) extract(identity plums.UserIdentity) string {
+	switch p.Rule {
+	case RuleAccountName:
+		cdsid, _, found := strings.Cut(identity.AccountName, p.Delimiter)
+		if !found {
+			return ""
+		}
+		return cdsid
+	case RuleRegex:
+		if p.pattern == nil {
+			return ""
+		}
+
+		match := p.pattern.FindStringSubmatch(identity.AccountName)
+		if len(match) < 2 {
+			return ""
+		}
+		return match[1]
+	case RuleProviderUserID:
+		return identity.ProviderUserID
+	}
+
+	return ""
+}
+
+// resolveCDSID returns the CDSID from the first provider in order that has one for the user.
+func resolveCDSID(providers []IdentityProvider, identities []plums.UserIdentity) string {
+	for i := range providers {
+		for _, identity := range identities {
+			if !strings.EqualFold(identity.Provider, providers[i].Name) {
+				continue
+			}
+
+			if cdsid := providers[i].extract(identity); cdsid != "" {
+				return cdsid
+			}
+		}
+	}
+
+	return ""
+}
+
+func toIdentities(identities []plums.UserIdentity) []Identity {
+	if len(identities) == 0 {
+		return nil
+	}
+
+	arr := make([]Identity, len(identities))
+	for i, identity := range identities {
+		arr[i] = Identity{
+			Provider:       identity.Provider,
+			ProviderUserID: identity.ProviderUserID,
+			AccountName:    identity.AccountName,
+		}
+	}
+
+	return arr
+}
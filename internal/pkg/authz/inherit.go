@@ -0,0 +1,58 @@
+package authz
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+// inheritance is an NSC partner of the user and the permission groups it passes down to the partners under its
+// distributor.
+type inheritance struct {
+	nsc              Partner
+	permissionGroups map[string][]string
+}
+
+// inheritAccess derives an inherited context with the permission groups of the NSC partner for every PARMA partner
+// under its distributor. Own partners of the user are skipped, every inherited context gets its own copy of the roles
+// and permission groups.
+func (s *Service) inheritAccess(ctx context.Context, from inheritance, own map[string]struct{}) ([]UserAccess, error) {
+	nsc := from.nsc
+	partners, err := s.cache.GetPartnersByDistributor(ctx, nsc.ID, store.PartnerTypeParma.String())
+	if err != nil {
+		return nil, err
+	}
+
+	accesses := make([]UserAccess, 0, len(partners))
+	for _, partner := range partners {
+		if _, ok := own[partner.ID]; ok {
+			continue
+		}
+
+		accesses = append(accesses, UserAccess{
+			Context: Context{
+				ID:            partner.ID,
+				Type:          store.PartnerTypeParma.String(),
+				Tag:           partner.ParmaPartnerCode,
+				Inherited:     true,
+				InheritedFrom: nsc.ID,
+			},
+			Roles:            slices.Clone(nsc.Roles),
+			PermissionGroups: clonePermissionGroups(from.permissionGroups),
+		})
+	}
+
+	return accesses, nil
+}
+
+// clonePermissionGroups copies the permission groups, so that every inherited context owns its own.
+func clonePermissionGroups(permissionGroups map[string][]string) map[string][]string {
+	clone := maps.Clone(permissionGroups)
+	for scope, groups := range clone {
+		clone[scope] = slices.Clone(groups)
+	}
+
+	return clone
+}
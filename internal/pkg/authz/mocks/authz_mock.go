@@ -52,6 +52,21 @@ func (mr *MockcacheClientMockRecorder) GetPartnersByCodes(ctx, partnerCodes, par
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPartnersByCodes", reflect.TypeOf((*MockcacheClient)(nil).GetPartnersByCodes), ctx, partnerCodes, partnerType)
 }
 
+// GetPartnersByDistributor mocks base method.
+func (m *MockcacheClient) GetPartnersByDistributor(ctx context.Context, distributorID, partnerType string) ([]*cachemanager.Partner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPartnersByDistributor", ctx, distributorID, partnerType)
+	ret0, _ := ret[0].([]*cachemanager.Partner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPartnersByDistributor indicates an expected call of GetPartnersByDistributor.
+func (mr *MockcacheClientMockRecorder) GetPartnersByDistributor(ctx, distributorID, partnerType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPartnersByDistributor", reflect.TypeOf((*MockcacheClient)(nil).GetPartnersByDistributor), ctx, distributorID, partnerType)
+}
+
 // MockplumsClient is a mock of plumsClient interface.
 type MockplumsClient struct {
 	ctrl     *gomock.Controller
@@ -75,19 +90,19 @@ func (m *MockplumsClient) EXPECT() *MockplumsClientMockRecorder {
 	return m.recorder
 }
 
-// GetUserByCDSID mocks base method.
-func (m *MockplumsClient) GetUserByCDSID(ctx context.Context, cdsid string) (*plums.User, error) {
+// GetUser mocks base method.
+func (m *MockplumsClient) GetUser(ctx context.Context, lookup plums.Lookup) (*plums.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByCDSID", ctx, cdsid)
+	ret := m.ctrl.Call(m, "GetUser", ctx, lookup)
 	ret0, _ := ret[0].(*plums.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByCDSID indicates an expected call of GetUserByCDSID.
-func (mr *MockplumsClientMockRecorder) GetUserByCDSID(ctx, cdsid interface{}) *gomock.Call {
+// GetUser indicates an expected call of GetUser.
+func (mr *MockplumsClientMockRecorder) GetUser(ctx, lookup interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByCDSID", reflect.TypeOf((*MockplumsClient)(nil).GetUserByCDSID), ctx, cdsid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockplumsClient)(nil).GetUser), ctx, lookup)
 }
 
 // MockauthzStore is a mock of authzStore interface.
@@ -113,6 +128,21 @@ func (m *MockauthzStore) EXPECT() *MockauthzStoreMockRecorder {
 	return m.recorder
 }
 
+// GetClient mocks base method.
+func (m *MockauthzStore) GetClient(key string) (store.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClient", key)
+	ret0, _ := ret[0].(store.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClient indicates an expected call of GetClient.
+func (mr *MockauthzStoreMockRecorder) GetClient(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClient", reflect.TypeOf((*MockauthzStore)(nil).GetClient), key)
+}
+
 // GetRoleMapping mocks base method.
 func (m *MockauthzStore) GetRoleMapping(scopeID, roleID string) (store.RoleMapping, error) {
 	m.ctrl.T.Helper()
@@ -142,3 +172,18 @@ func (mr *MockauthzStoreMockRecorder) GetRoleMappings(scopeID interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleMappings", reflect.TypeOf((*MockauthzStore)(nil).GetRoleMappings), scopeID)
 }
+
+// GetScope mocks base method.
+func (m *MockauthzStore) GetScope(key string) (store.Scope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScope", key)
+	ret0, _ := ret[0].(store.Scope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScope indicates an expected call of GetScope.
+func (mr *MockauthzStoreMockRecorder) GetScope(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScope", reflect.TypeOf((*MockauthzStore)(nil).GetScope), key)
+}
@@ -1,23 +1,66 @@
 package authz
 
+import "github.com/volvo-cars/connect-access-control/internal/pkg/store"
+
+// Access is the evaluated access of a user together with the user it was evaluated for.
+type Access struct {
+	User     User
+	Accesses []UserAccess
+}
+
+// ClientAccess is the evaluated access of a user for the dependant scopes of a client.
+type ClientAccess struct {
+	Client store.Client
+	Access
+}
+
 type UserAccess struct {
 	Context          Context
 	Roles            []string
 	PermissionGroups map[string][]string
+	// Domains are the access domains of the data scopes, keyed by scope.
+	Domains map[string]AccessDomain
+}
+
+// AccessDomain is the data visible to a user within a context of a data scope.
+type AccessDomain struct {
+	Partners     []string
+	Distributors []string
+	Markets      []string
 }
 
 type Context struct {
 	ID   string
 	Type string
 	Tag  string
+	// Inherited is set on contexts derived from the distributor of an NSC context of the user, InheritedFrom is the ID
+	// of that NSC context.
+	Inherited     bool
+	InheritedFrom string
 }
 
 type User struct {
-	ID          string
-	Email       string
-	CDSID       string
-	CountryCode string
-	Partners    []Partner
+	ID        string
+	FirstName string
+	LastName  string
+	Email     string
+	CDSID     string
+	// CDSIDUnresolved is set when none of the identities of the user yields a CDSID, CDSID is empty then.
+	CDSIDUnresolved bool
+	CountryCode     string
+	Partners        []Partner
+	Identities      []Identity
+	// UnresolvedPartners are the PLUMS partner IDs that cache-manager did not return.
+	UnresolvedPartners []string
+	// Stale is set when the user data was served from the cache past its TTL, e.g. while PLUMS is unavailable.
+	Stale bool
+}
+
+// Identity is an account of the user at an identity provider.
+type Identity struct {
+	Provider       string
+	ProviderUserID string
+	AccountName    string
 }
 
 type Partner struct {
@@ -31,4 +74,19 @@ type Partner struct {
 	IsPrimary        bool
 	Active           bool
 	Roles            []string
+	Address          Address
+}
+
+type Address struct {
+	CountryName  string
+	AddressLine1 string
+	AddressLine2 string
+	AddressLine3 string
+	AddressLine4 string
+	City         string
+	District     string
+	State        string
+	PostalCode   string
+	CountryCode  string
+	LanguageCode string
 }
@@ -0,0 +1,29 @@
+package cors
+
+import (
+	"time"
+
+	env "github.com/caarlos0/env/v11"
+)
+
+type Config struct {
+	// Enabled answers cross-origin requests of the whitelisted domains of the clients in the store.
+	Enabled bool `env:"CORS_ENABLED" envDefault:"true"`
+	// AllowInsecure also allows http origins of the whitelisted domains, e.g. for local front-ends.
+	AllowInsecure bool `env:"CORS_ALLOW_INSECURE" envDefault:"false"`
+	// AllowCredentials lets browsers send cookies, the API authenticates with bearer tokens and needs none.
+	AllowCredentials bool          `env:"CORS_ALLOW_CREDENTIALS" envDefault:"false"`
+	AllowedMethods   []string      `env:"CORS_ALLOWED_METHODS" envDefault:"GET,POST,OPTIONS"`
+	AllowedHeaders   []string      `env:"CORS_ALLOWED_HEADERS" envDefault:"Authorization,Content-Type,If-None-Match,X-Request-Id,X-Correlation-Id"`
+	ExposedHeaders   []string      `env:"CORS_EXPOSED_HEADERS" envDefault:"ETag,X-Data-Stale,X-Request-Id,X-Correlation-Id"`
+	MaxAge           time.Duration `env:"CORS_MAX_AGE" envDefault:"10m"`
+}
+
+func LoadConfig() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
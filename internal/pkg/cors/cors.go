@@ -0,0 +1,169 @@
+package cors
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+type clientStore interface {
+	GetClients() ([]store.Client, error)
+	Revision() uint64
+}
+
+// Policy allows the cross-origin requests of the whitelisted domains of all clients. The domains are read again
+// whenever the store was reloaded.
+type Policy struct {
+	cfg   *Config
+	store clientStore
+
+	mu       sync.RWMutex
+	revision uint64
+	domains  domains
+}
+
+func New(cfg *Config, store clientStore) *Policy {
+	return &Policy{cfg: cfg, store: store}
+}
+
+// domains are the whitelisted hosts, wildcards hold the parent domains of the wildcard domains with a leading dot.
+type domains struct {
+	hosts     map[string]struct{}
+	wildcards []string
+}
+
+func (d domains) allows(host string) bool {
+	if _, ok := d.hosts[host]; ok {
+		return true
+	}
+
+	for _, suffix := range d.wildcards {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allowed reports whether the origin is one of the whitelisted domains, *.example.com allows every subdomain of
+// example.com but not example.com itself.
+func (p *Policy) Allowed(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !p.cfg.AllowInsecure {
+			return false
+		}
+	default:
+		return false
+	}
+
+	return p.current().allows(strings.ToLower(u.Hostname()))
+}
+
+func (p *Policy) current() domains {
+	revision := p.store.Revision()
+
+	p.mu.RLock()
+	if p.domains.hosts != nil && p.revision == revision {
+		defer p.mu.RUnlock()
+		return p.domains
+	}
+	p.mu.RUnlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.domains.hosts == nil || p.revision != revision {
+		// the previous domains are kept when the clients fail to load, they are loaded again with the next request
+		d, err := p.load()
+		if err != nil {
+			slog.Error("failed to load the whitelisted domains of the clients", slog.Any("error", err))
+			return p.domains
+		}
+
+		p.domains = d
+		p.revision = revision
+	}
+
+	return p.domains
+}
+
+func (p *Policy) load() (domains, error) {
+	clients, err := p.store.GetClients()
+	if err != nil {
+		return domains{}, err
+	}
+
+	d := domains{hosts: make(map[string]struct{})}
+	for _, client := range clients {
+		for _, domain := range client.WhitelistedDomains {
+			domain = strings.ToLower(domain)
+			if parent, ok := strings.CutPrefix(domain, "*."); ok {
+				d.wildcards = append(d.wildcards, "."+parent)
+				continue
+			}
+			d.hosts[domain] = struct{}{}
+		}
+	}
+
+	return d, nil
+}
+
+// Middleware adds the CORS headers to the responses of allowed origins and answers their preflight requests. The
+// preflight requests of other origins are rejected, their other requests are served without CORS headers so that
+// browsers block the response.
+func (p *Policy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if !p.Allowed(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if p.cfg.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if len(p.cfg.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(p.cfg.ExposedHeaders, ", "))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(p.cfg.AllowedMethods, ", "))
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(p.cfg.AllowedHeaders, ", "))
+		if p.cfg.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(p.cfg.MaxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,63 @@
+package cors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+type fakeStore struct {
+	clients  []store.Client
+	err      error
+	revision uint64
+}
+
+func (s *fakeStore) GetClients() ([]store.Client, error) {
+	return s.clients, s.err
+}
+
+func (s *fakeStore) Revision() uint64 {
+	return s.revision
+}
+
+func TestAllowed(t *testing.T) {
+	clients := &fakeStore{clients: []store.Client{{WhitelistedDomains: []string{"portal.example.com", "*.Apps.example.com"}}}}
+
+	tests := map[string]struct {
+		origin        string
+		allowInsecure bool
+		allowed       bool
+	}{
+		"whitelisted host":              {origin: "https://portal.example.com", allowed: true},
+		"whitelisted host with port":    {origin: "https://portal.example.com:8443", allowed: true},
+		"subdomain of a wildcard":       {origin: "https://one.apps.example.com", allowed: true},
+		"parent of a wildcard":          {origin: "https://apps.example.com"},
+		"suffix of a whitelisted host":  {origin: "https://evilportal.example.com"},
+		"http origin":                   {origin: "http://portal.example.com"},
+		"http origin allowing insecure": {origin: "http://portal.example.com", allowInsecure: true, allowed: true},
+		"other scheme":                  {origin: "file://portal.example.com"},
+		"invalid origin":                {origin: "portal.example.com"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy := New(&Config{AllowInsecure: tt.allowInsecure}, clients)
+			assert.Equal(t, tt.allowed, policy.Allowed(tt.origin))
+		})
+	}
+}
+
+func TestAllowedKeepsDomainsWhenClientsFailToLoad(t *testing.T) {
+	clients := &fakeStore{clients: []store.Client{{WhitelistedDomains: []string{"portal.example.com"}}}, revision: 1}
+	policy := New(&Config{}, clients)
+	assert.True(t, policy.Allowed("https://portal.example.com"))
+
+	clients.clients, clients.err, clients.revision = nil, errors.New("store failed"), 2
+	assert.True(t, policy.Allowed("https://portal.example.com"))
+
+	clients.clients, clients.err = []store.Client{{WhitelistedDomains: []string{"other.example.com"}}}, nil
+	assert.False(t, policy.Allowed("https://portal.example.com"))
+	assert.True(t, policy.Allowed("https://other.example.com"))
+}
@@ -0,0 +1,166 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+const (
+	// TypeConfigRevision is the event of a new revision of the IAM configuration.
+	TypeConfigRevision = "config.revision"
+	// TypeConfigReset tells a resuming consumer that it missed revisions, it must drop everything it cached.
+	TypeConfigReset = "config.reset"
+)
+
+type revisionStore interface {
+	Revision() uint64
+	Digest() string
+}
+
+// Event is a change of the IAM configuration, its ID is unique across restarts of the service.
+type Event struct {
+	ID     string
+	Type   string
+	Change store.Change
+}
+
+// Broker passes the changes of the store on to the subscribers of the event stream and keeps the latest events in a
+// ring buffer, so that consumers that reconnect resume after the last event they received.
+type Broker struct {
+	// epoch tells the event IDs of this process apart from the IDs of earlier processes, which counted revisions
+	// from the start as well.
+	epoch string
+	size  int
+
+	mu sync.Mutex
+	// latest is the last published change, replays are based on it rather than on the revision of the store, which
+	// is incremented before the change is published.
+	latest      store.Change
+	buffer      []Event
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+// NewBroker returns a broker that starts at the current revision of the store.
+func NewBroker(revisions revisionStore, size int) *Broker {
+	return &Broker{
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		size:        max(size, 1),
+		latest:      store.Change{Revision: revisions.Revision(), Digest: revisions.Digest()},
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Publish buffers the event of the change and sends it to the subscribers. Subscribers that did not keep up are
+// unsubscribed, their channel is closed so that they reconnect and resume from the buffer.
+func (b *Broker) Publish(change store.Change) {
+	event := Event{ID: b.eventID(change.Revision), Type: TypeConfigRevision, Change: change}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.latest = change
+	b.buffer = append(b.buffer, event)
+	if len(b.buffer) > b.size {
+		b.buffer = b.buffer[len(b.buffer)-b.size:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe returns the events to replay after the lastEventID and the channel of the events that follow, cancel
+// unsubscribes. Without a lastEventID nothing is replayed, when the events after it are no longer buffered, or it is
+// of an earlier process, a single config.reset event is replayed instead.
+func (b *Broker) Subscribe(lastEventID string) (replay []Event, events <-chan Event, cancel func()) {
+	ch := make(chan Event, b.size)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if lastEventID != "" {
+		replay = b.replay(lastEventID)
+	}
+
+	if b.closed {
+		close(ch)
+		return replay, ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return replay, ch, cancel
+}
+
+// replay returns the buffered events after the last event ID, or a reset event when they cannot be replayed.
+func (b *Broker) replay(lastEventID string) []Event {
+	revision := b.latest.Revision
+
+	epoch, raw, _ := strings.Cut(lastEventID, "-")
+	last, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || epoch != b.epoch || last > revision {
+		return []Event{b.reset()}
+	}
+
+	var replay []Event
+	for _, event := range b.buffer {
+		if event.Change.Revision > last {
+			replay = append(replay, event)
+		}
+	}
+
+	if last < revision && (len(replay) == 0 || replay[0].Change.Revision != last+1) {
+		return []Event{b.reset()}
+	}
+
+	return replay
+}
+
+func (b *Broker) reset() Event {
+	return Event{
+		ID:     b.eventID(b.latest.Revision),
+		Type:   TypeConfigReset,
+		Change: store.Change{Revision: b.latest.Revision, Digest: b.latest.Digest},
+	}
+}
+
+// Close closes the channels of all subscribers, so that their streams end, and drops the events published after it.
+// Subscribers after Close get a closed channel.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (b *Broker) eventID(revision uint64) string {
+	return fmt.Sprintf("%s-%d", b.epoch, revision)
+}
@@ -0,0 +1,157 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+type fakeStore struct {
+	revision uint64
+	digest   string
+}
+
+func (s fakeStore) Revision() uint64 {
+	return s.revision
+}
+
+func (s fakeStore) Digest() string {
+	return s.digest
+}
+
+// newBroker returns a broker that buffers size events, after the revisions 2 to 5 were published.
+func newBroker(size int) *Broker {
+	b := NewBroker(fakeStore{revision: 1, digest: "d1"}, size)
+	for revision := uint64(2); revision <= 5; revision++ {
+		b.Publish(store.Change{Revision: revision})
+	}
+
+	return b
+}
+
+func revisions(events []Event) []uint64 {
+	var revisions []uint64
+	for _, event := range events {
+		revisions = append(revisions, event.Change.Revision)
+	}
+
+	return revisions
+}
+
+func TestSubscribeReplay(t *testing.T) {
+	b := newBroker(3)
+
+	tests := map[string]struct {
+		lastEventID string
+		reset       bool
+		revisions   []uint64
+	}{
+		"without last event ID":       {},
+		"buffered events":             {lastEventID: b.eventID(3), revisions: []uint64{4, 5}},
+		"oldest buffered event":       {lastEventID: b.eventID(2), revisions: []uint64{3, 4, 5}},
+		"latest event":                {lastEventID: b.eventID(5)},
+		"events no longer buffered":   {lastEventID: b.eventID(1), reset: true},
+		"revision after the latest":   {lastEventID: b.eventID(6), reset: true},
+		"event of an earlier process": {lastEventID: "0-4", reset: true},
+		"invalid event ID":            {lastEventID: "revision", reset: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			replay, _, cancel := b.Subscribe(tt.lastEventID)
+			defer cancel()
+
+			if !tt.reset {
+				assert.Equal(t, tt.revisions, revisions(replay))
+				for _, event := range replay {
+					assert.Equal(t, TypeConfigRevision, event.Type)
+				}
+				return
+			}
+
+			require.Len(t, replay, 1)
+			assert.Equal(t, TypeConfigReset, replay[0].Type)
+			assert.Equal(t, b.eventID(5), replay[0].ID)
+			assert.EqualValues(t, 5, replay[0].Change.Revision)
+		})
+	}
+}
+
+func TestResetCarriesLatestDigest(t *testing.T) {
+	b := NewBroker(fakeStore{revision: 1, digest: "d1"}, 3)
+
+	replay, _, cancel := b.Subscribe("0-1")
+	cancel()
+	require.Len(t, replay, 1)
+	assert.Equal(t, "d1", replay[0].Change.Digest)
+
+	// the revision of the store may be ahead of the latest published change
+	b.Publish(store.Change{Revision: 2, Digest: "d2"})
+	replay, _, cancel = b.Subscribe("0-1")
+	cancel()
+	require.Len(t, replay, 1)
+	assert.Equal(t, store.Change{Revision: 2, Digest: "d2"}, replay[0].Change)
+}
+
+func TestPublish(t *testing.T) {
+	b := NewBroker(fakeStore{revision: 1}, 2)
+	_, events, cancel := b.Subscribe("")
+	defer cancel()
+
+	b.Publish(store.Change{Revision: 2})
+	event := <-events
+	assert.Equal(t, b.eventID(2), event.ID)
+	assert.Equal(t, TypeConfigRevision, event.Type)
+
+	// subscribers that did not keep up are unsubscribed
+	for revision := uint64(3); revision <= 5; revision++ {
+		b.Publish(store.Change{Revision: revision})
+	}
+
+	assert.Equal(t, []uint64{3, 4}, revisions(drain(events)))
+	assert.Empty(t, b.subscribers)
+
+	// cancelling an unsubscribed channel does not close it again
+	assert.NotPanics(t, cancel)
+}
+
+func TestCancel(t *testing.T) {
+	b := NewBroker(fakeStore{revision: 1}, 2)
+	_, events, cancel := b.Subscribe("")
+
+	cancel()
+	cancel()
+
+	_, ok := <-events
+	assert.False(t, ok)
+	assert.Empty(t, b.subscribers)
+}
+
+func TestClose(t *testing.T) {
+	b := newBroker(3)
+	_, events, cancel := b.Subscribe("")
+	defer cancel()
+
+	b.Close()
+	assert.Empty(t, drain(events))
+
+	// events published after Close are dropped
+	b.Publish(store.Change{Revision: 6})
+	replay, events, _ := b.Subscribe(b.eventID(4))
+	assert.Equal(t, []uint64{5}, revisions(replay))
+
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+// drain returns the events of a closed channel.
+func drain(events <-chan Event) []Event {
+	var drained []Event
+	for event := range events {
+		drained = append(drained, event)
+	}
+
+	return drained
+}
@@ -0,0 +1,181 @@
+package extauthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/auth"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+var (
+	ErrRouteNotDeclared = errors.New("no route is declared for the request")
+	ErrNotGranted       = errors.New("permission group of the route is not granted")
+	ErrInvalidPath      = errors.New("request path is invalid")
+)
+
+type routeStore interface {
+	GetRoutes() ([]store.Route, error)
+	Revision() uint64
+}
+
+type authenticator interface {
+	Authenticate(ctx context.Context, token string) (auth.Principal, error)
+	Lookup(principal auth.Principal) (plums.Lookup, error)
+}
+
+type authzClient interface {
+	GetUserAccess(ctx context.Context, lookup plums.Lookup, scopes []string, opts ...authz.AccessOption) (authz.Access, error)
+}
+
+// Request is the original request the gateway asks to authorize.
+type Request struct {
+	Host   string
+	Method string
+	// Path is the escaped path of the request, as sent by the caller.
+	Path          string
+	Authorization string
+}
+
+// Result is an allowed request, with the identity the gateway passes on to the upstream service.
+type Result struct {
+	Subject  string
+	ClientID string
+	// Contexts are the IDs of the contexts granting a permission group of the route.
+	Contexts []string
+	// Route is the matched route, nil when the request was allowed by default.
+	Route *store.Route
+}
+
+// Authorizer decides the requests of the gateway against the routes of the clients and scopes. The routes are read
+// again whenever the store was reloaded.
+type Authorizer struct {
+	cfg           *Config
+	store         routeStore
+	authenticator authenticator
+	authzClient   authzClient
+
+	mu       sync.RWMutex
+	revision uint64
+	loaded   bool
+	routes   []route
+}
+
+func New(cfg *Config, store routeStore, authenticator authenticator, authzClient authzClient) *Authorizer {
+	return &Authorizer{
+		cfg:           cfg,
+		store:         store,
+		authenticator: authenticator,
+		authzClient:   authzClient,
+	}
+}
+
+// Authorize authenticates the bearer token of the request and checks that its subject is granted one of the
+// permission groups of the most specific matching route, in any of the contexts of the user. The user of the token is
+// only resolved for routes with permission groups. The result of a denied request holds the authenticated caller.
+func (a *Authorizer) Authorize(ctx context.Context, req Request) (Result, error) {
+	scheme, token, ok := strings.Cut(req.Authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return Result{}, auth.ErrMissingToken
+	}
+
+	principal, err := a.authenticator.Authenticate(ctx, strings.TrimSpace(token))
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Subject: principal.Subject, ClientID: principal.ClientID}
+
+	segments, err := cleanPath(req.Path)
+	if err != nil {
+		return result, err
+	}
+
+	matched, ok := a.match(principal.ClientID, req, segments)
+	if !ok {
+		if a.cfg.DefaultAllow {
+			return result, nil
+		}
+		return result, fmt.Errorf("%w: [%s %s]", ErrRouteNotDeclared, req.Method, req.Path)
+	}
+	result.Route = &matched.Route
+
+	// callers without a user, e.g. client credentials, only pass routes without permission groups
+	if len(matched.PermissionGroups) == 0 {
+		return result, nil
+	}
+
+	lookup, err := a.authenticator.Lookup(principal)
+	if err != nil {
+		return result, err
+	}
+
+	access, err := a.authzClient.GetUserAccess(ctx, lookup, []string{matched.Scope})
+	if err != nil {
+		return result, err
+	}
+
+	if access.User.CDSID != "" {
+		result.Subject = access.User.CDSID
+	}
+
+	for _, userAccess := range access.Accesses {
+		granted := userAccess.PermissionGroups[matched.Scope]
+		if slices.ContainsFunc(matched.PermissionGroups, func(group string) bool { return slices.Contains(granted, group) }) {
+			result.Contexts = append(result.Contexts, userAccess.Context.ID)
+		}
+	}
+
+	if len(result.Contexts) == 0 {
+		return result, fmt.Errorf("%w: route [%s] requires one of [%s] of scope [%s]", ErrNotGranted, matched.Path, strings.Join(matched.PermissionGroups, ", "), matched.Scope)
+	}
+
+	return result, nil
+}
+
+// match returns the most specific route of the request with the path segments.
+func (a *Authorizer) match(clientID string, req Request, segments []string) (route, bool) {
+	for _, r := range a.current() {
+		if r.matches(clientID, req.Host, req.Method, segments) {
+			return r, true
+		}
+	}
+
+	return route{}, false
+}
+
+func (a *Authorizer) current() []route {
+	revision := a.store.Revision()
+
+	a.mu.RLock()
+	if a.loaded && a.revision == revision {
+		defer a.mu.RUnlock()
+		return a.routes
+	}
+	a.mu.RUnlock()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.loaded || a.revision != revision {
+		declared, _ := a.store.GetRoutes()
+
+		routes := make([]route, len(declared))
+		for i, r := range declared {
+			routes[i] = newRoute(r)
+		}
+		slices.SortFunc(routes, compare)
+
+		a.routes = routes
+		a.revision = revision
+		a.loaded = true
+	}
+
+	return a.routes
+}
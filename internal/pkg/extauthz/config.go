@@ -0,0 +1,24 @@
+package extauthz
+
+import (
+	env "github.com/caarlos0/env/v11"
+)
+
+type Config struct {
+	// Enabled serves the Envoy HTTP ext_authz endpoint under PathPrefix, it requires authentication to be enabled.
+	Enabled bool `env:"EXTAUTHZ_ENABLED" envDefault:"false"`
+	// PathPrefix is the path_prefix of the http_service of the Envoy ext_authz filter, the path of the original request
+	// follows it.
+	PathPrefix string `env:"EXTAUTHZ_PATH_PREFIX" envDefault:"/ext-authz"`
+	// DefaultAllow allows authenticated requests that match no route, they are denied otherwise.
+	DefaultAllow bool `env:"EXTAUTHZ_DEFAULT_ALLOW" envDefault:"false"`
+}
+
+func LoadConfig() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,160 @@
+package extauthz
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+// route is a store route with its path pattern split into segments.
+type route struct {
+	store.Route
+	segments []string
+	// rest is set for patterns ending in **, which match any remaining segments.
+	rest bool
+}
+
+func newRoute(r store.Route) route {
+	segments := splitPath(r.Path)
+	rest := len(segments) > 0 && segments[len(segments)-1] == "**"
+	if rest {
+		segments = segments[:len(segments)-1]
+	}
+
+	return route{Route: r, segments: segments, rest: rest}
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+// cleanPath returns the decoded segments of an escaped request path. Paths with encoded slashes or backslashes or with
+// .. segments are rejected, since the upstream may resolve them to another route than the one they were matched by.
+// Empty and . segments are dropped.
+func cleanPath(escaped string) ([]string, error) {
+	lower := strings.ToLower(escaped)
+	if strings.Contains(lower, "%2f") || strings.Contains(lower, "%5c") || strings.Contains(escaped, `\`) {
+		return nil, fmt.Errorf("%w: encoded slash in [%s]", ErrInvalidPath, escaped)
+	}
+
+	path, err := url.PathUnescape(escaped)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPath, err)
+	}
+
+	var segments []string
+	for _, segment := range splitPath(path) {
+		switch segment {
+		case "", ".":
+		case "..":
+			return nil, fmt.Errorf("%w: .. segment in [%s]", ErrInvalidPath, escaped)
+		default:
+			segments = append(segments, segment)
+		}
+	}
+
+	return segments, nil
+}
+
+// matches reports whether the route applies to the request of the client.
+func (r route) matches(clientID, host, method string, segments []string) bool {
+	if r.Client != "" && r.Client != clientID {
+		return false
+	}
+
+	if r.Host != "" && !matchesHost(r.Host, host) {
+		return false
+	}
+
+	if len(r.Methods) > 0 && !slices.ContainsFunc(r.Methods, func(m string) bool { return strings.EqualFold(m, method) }) {
+		return false
+	}
+
+	if len(segments) < len(r.segments) || (!r.rest && len(segments) != len(r.segments)) {
+		return false
+	}
+
+	for i, segment := range r.segments {
+		if segment == "*" || isParam(segment) {
+			continue
+		}
+
+		if segment != segments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesHost compares the hosts with their ports, or without the port of the request when the route has none.
+func matchesHost(routeHost, host string) bool {
+	if strings.EqualFold(routeHost, host) {
+		return true
+	}
+
+	hostname, _, err := net.SplitHostPort(host)
+	return err == nil && strings.EqualFold(routeHost, hostname)
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// compare orders routes from the most to the least specific: routes of a client before shared routes, routes with a
+// host or methods before routes without, more literal segments before wildcards and exact patterns before patterns
+// ending in **.
+func compare(a, b route) int {
+	if c := compareFlag(a.Client != "", b.Client != ""); c != 0 {
+		return c
+	}
+
+	if c := compareFlag(a.Host != "", b.Host != ""); c != 0 {
+		return c
+	}
+
+	for i := 0; i < len(a.segments) && i < len(b.segments); i++ {
+		if c := compareFlag(literal(a.segments[i]), literal(b.segments[i])); c != 0 {
+			return c
+		}
+	}
+
+	if len(a.segments) != len(b.segments) {
+		return len(b.segments) - len(a.segments)
+	}
+
+	if c := compareFlag(!a.rest, !b.rest); c != 0 {
+		return c
+	}
+
+	if c := compareFlag(len(a.Methods) > 0, len(b.Methods) > 0); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.Path, b.Path)
+}
+
+// compareFlag orders the set flag first.
+func compareFlag(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func literal(segment string) bool {
+	return segment != "*" && !isParam(segment)
+}
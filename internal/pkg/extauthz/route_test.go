@@ -0,0 +1,180 @@
+package extauthz
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+)
+
+func TestCleanPath(t *testing.T) {
+	tests := map[string]struct {
+		segments []string
+		invalid  bool
+	}{
+		"/":                        {},
+		"/users/jsmith":            {segments: []string{"users", "jsmith"}},
+		"//users///jsmith/":        {segments: []string{"users", "jsmith"}},
+		"/users/./jsmith":          {segments: []string{"users", "jsmith"}},
+		"/users/j%20smith":         {segments: []string{"users", "j smith"}},
+		"/users/%2e/jsmith":        {segments: []string{"users", "jsmith"}},
+		"/users/../admin":          {invalid: true},
+		"/users/%2e%2e/admin":      {invalid: true},
+		"/users/%2E%2E/admin":      {invalid: true},
+		"/users/..":                {invalid: true},
+		"/users%2fadmin":           {invalid: true},
+		"/users%2Fadmin":           {invalid: true},
+		"/users%5cadmin":           {invalid: true},
+		`/users\admin`:             {invalid: true},
+		"/users/%zz":               {invalid: true},
+		"/users/jsmith..":          {segments: []string{"users", "jsmith.."}},
+		"/users/..jsmith/settings": {segments: []string{"users", "..jsmith", "settings"}},
+	}
+
+	for path, tt := range tests {
+		t.Run(path, func(t *testing.T) {
+			segments, err := cleanPath(path)
+			if tt.invalid {
+				assert.ErrorIs(t, err, ErrInvalidPath)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.segments, segments)
+		})
+	}
+}
+
+func TestRouteMatches(t *testing.T) {
+	type request struct {
+		clientID, host, method, path string
+	}
+
+	tests := map[string]struct {
+		route   store.Route
+		request request
+		matches bool
+	}{
+		"literal path": {
+			route:   store.Route{Path: "/users/me"},
+			request: request{method: "GET", path: "/users/me"},
+			matches: true,
+		},
+		"other literal": {
+			route:   store.Route{Path: "/users/me"},
+			request: request{method: "GET", path: "/users/you"},
+		},
+		"parameter": {
+			route:   store.Route{Path: "/users/{id}/roles"},
+			request: request{method: "GET", path: "/users/jsmith/roles"},
+			matches: true,
+		},
+		"wildcard": {
+			route:   store.Route{Path: "/users/*"},
+			request: request{method: "GET", path: "/users/jsmith"},
+			matches: true,
+		},
+		"wildcard matches a single segment": {
+			route:   store.Route{Path: "/users/*"},
+			request: request{method: "GET", path: "/users/jsmith/roles"},
+		},
+		"rest": {
+			route:   store.Route{Path: "/users/**"},
+			request: request{method: "GET", path: "/users/jsmith/roles"},
+			matches: true,
+		},
+		"rest matches no segments": {
+			route:   store.Route{Path: "/users/**"},
+			request: request{method: "GET", path: "/users"},
+			matches: true,
+		},
+		"shorter path": {
+			route:   store.Route{Path: "/users/{id}"},
+			request: request{method: "GET", path: "/users"},
+		},
+		"method": {
+			route:   store.Route{Path: "/users", Methods: []string{"post", "PUT"}},
+			request: request{method: "POST", path: "/users"},
+			matches: true,
+		},
+		"other method": {
+			route:   store.Route{Path: "/users", Methods: []string{"POST"}},
+			request: request{method: "DELETE", path: "/users"},
+		},
+		"host": {
+			route:   store.Route{Host: "api.example.com", Path: "/users"},
+			request: request{host: "API.example.com", method: "GET", path: "/users"},
+			matches: true,
+		},
+		"host with port": {
+			route:   store.Route{Host: "api.example.com", Path: "/users"},
+			request: request{host: "api.example.com:8443", method: "GET", path: "/users"},
+			matches: true,
+		},
+		"other port": {
+			route:   store.Route{Host: "api.example.com:443", Path: "/users"},
+			request: request{host: "api.example.com:8443", method: "GET", path: "/users"},
+		},
+		"other host": {
+			route:   store.Route{Host: "api.example.com", Path: "/users"},
+			request: request{host: "admin.example.com", method: "GET", path: "/users"},
+		},
+		"client": {
+			route:   store.Route{Path: "/users", Client: "dealer-app"},
+			request: request{clientID: "dealer-app", method: "GET", path: "/users"},
+			matches: true,
+		},
+		"other client": {
+			route:   store.Route{Path: "/users", Client: "dealer-app"},
+			request: request{clientID: "user-portal", method: "GET", path: "/users"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			segments, err := cleanPath(tt.request.path)
+			assert.NoError(t, err)
+
+			matches := newRoute(tt.route).matches(tt.request.clientID, tt.request.host, tt.request.method, segments)
+			assert.Equal(t, tt.matches, matches)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	routes := []route{
+		newRoute(store.Route{Path: "/users/**"}),
+		newRoute(store.Route{Path: "/users/{id}"}),
+		newRoute(store.Route{Path: "/users/me", Methods: []string{"GET"}}),
+		newRoute(store.Route{Path: "/users/me"}),
+		newRoute(store.Route{Path: "/users/{id}/roles"}),
+		newRoute(store.Route{Host: "api.example.com", Path: "/**"}),
+		newRoute(store.Route{Path: "/**", Client: "dealer-app"}),
+	}
+
+	slices.SortFunc(routes, compare)
+
+	var paths []string
+	for _, r := range routes {
+		paths = append(paths, r.Client+r.Host+r.Path+fmtMethods(r.Methods))
+	}
+
+	assert.Equal(t, []string{
+		"dealer-app/**",
+		"api.example.com/**",
+		"/users/me GET",
+		"/users/me",
+		"/users/{id}/roles",
+		"/users/{id}",
+		"/users/**",
+	}, paths)
+}
+
+func fmtMethods(methods []string) string {
+	if len(methods) == 0 {
+		return ""
+	}
+
+	return " " + methods[0]
+}
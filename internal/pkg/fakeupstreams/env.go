@@ -0,0 +1,20 @@
+package fakeupstreams
+
+// Env returns the environment that points the PLUMS and cache-manager gateways at fake upstreams served on baseURL.
+func Env(baseURL string) map[string]string {
+	return map[string]string{
+		"PLUMS_BASE_URL":      baseURL + PlumsPath,
+		"PLUMS_ISSUER":        baseURL,
+		"PLUMS_TOKEN_URL":     baseURL + TokenPath,
+		"PLUMS_CLIENT_ID":     "fake-client",
+		"PLUMS_CLIENT_SECRET": "fake-secret",
+		"PLUMS_USER_KEY":      "fake-user-key",
+		"PLUMS_AUDIENCE":      "fake-audience",
+		"PLUMS_SCOPES":        "fake-scope",
+		"CACHE_BASE_URL":      baseURL + CacheManagerPath,
+		"CACHE_TOKEN_URL":     baseURL + TokenPath,
+		"CACHE_CLIENT_ID":     "fake-client",
+		"CACHE_CLIENT_SECRET": "fake-secret",
+		"CACHE_SCOPES":        "fake-scope",
+	}
+}
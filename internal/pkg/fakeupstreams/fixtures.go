@@ -0,0 +1,26 @@
+package fakeupstreams
+
+import (
+	"fmt"
+
+	cachemanager "github.com/volvo-cars/connect-access-control/internal/pkg/gateway/cache-manager"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/utils"
+)
+
+// Fixtures is the data served by the fake upstreams, users and roles are served as PLUMS and partners as cache-manager.
+type Fixtures struct {
+	Roles    []plums.Role                `json:"roles"`
+	Users    []plums.User                `json:"users"`
+	Partners []cachemanager.TypedPartner `json:"partners"`
+}
+
+// LoadFixtures reads fixtures from a YAML file.
+func LoadFixtures(filePath string) (*Fixtures, error) {
+	fixtures, err := utils.YAMLUnmarshal[Fixtures](filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fixtures file [%s]: %w", filePath, err)
+	}
+
+	return &fixtures, nil
+}
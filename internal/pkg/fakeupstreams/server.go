@@ -0,0 +1,156 @@
+package fakeupstreams
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	cachemanager "github.com/volvo-cars/connect-access-control/internal/pkg/gateway/cache-manager"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+)
+
+const (
+	PlumsPath        = "/plums"
+	CacheManagerPath = "/cache-manager/partners"
+	TokenPath        = "/connect/token"
+
+	fakeAccessToken = "fake-access-token"
+	tokenExpiresIn  = 3600
+)
+
+// Server serves the PLUMS and cache-manager endpoints used by the gateways, together with an OAuth2 token endpoint.
+type Server struct {
+	fixtures *Fixtures
+}
+
+func New(fixtures *Fixtures) *Server {
+	return &Server{
+		fixtures: fixtures,
+	}
+}
+
+// NewTestServer starts the fake upstreams on a random local port, close it when done.
+func NewTestServer(fixtures *Fixtures) *httptest.Server {
+	return httptest.NewServer(New(fixtures).Handler())
+}
+
+func (s *Server) Handler() http.Handler {
+	r := chi.NewRouter()
+
+	r.Post(TokenPath, s.token)
+
+	r.Route(PlumsPath, func(r chi.Router) {
+		r.Get("/users", s.searchUsers)
+		r.Get("/users/by-cdsid/{cdsid}", s.getUserByCDSID)
+		r.Get("/users/{userID}", s.getUserByID)
+		r.Get("/roles", s.getRoles)
+	})
+
+	r.Get(CacheManagerPath, s.getPartners)
+
+	return r
+}
+
+func (s *Server) token(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": fakeAccessToken,
+		"token_type":   "Bearer",
+		"expires_in":   tokenExpiresIn,
+	})
+}
+
+func (s *Server) getUserByCDSID(w http.ResponseWriter, r *http.Request) {
+	s.getUser(w, plums.ByCDSID(chi.URLParam(r, "cdsid")))
+}
+
+func (s *Server) getUserByID(w http.ResponseWriter, r *http.Request) {
+	s.getUser(w, plums.ByUserID(chi.URLParam(r, "userID")))
+}
+
+func (s *Server) getUser(w http.ResponseWriter, lookup plums.Lookup) {
+	for _, user := range s.fixtures.Users {
+		if lookup.Matches(&user) {
+			writeJSON(w, http.StatusOK, user)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusNotFound, map[string]string{"message": "user not found"})
+}
+
+// searchUsers serves the PLUMS user search by email or by provider identity.
+func (s *Server) searchUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	lookup := plums.ByIdentity(query.Get("provider"), query.Get("providerUserId"))
+	if email := query.Get("email"); email != "" {
+		lookup = plums.ByEmail(email)
+	}
+
+	result := make([]*plums.User, 0)
+	for _, user := range s.fixtures.Users {
+		if lookup.Matches(&user) {
+			result = append(result, &user)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, plums.Users{Result: result})
+}
+
+func (s *Server) getRoles(w http.ResponseWriter, _ *http.Request) {
+	roles := s.fixtures.Roles
+	if roles == nil {
+		roles = []plums.Role{}
+	}
+
+	writeJSON(w, http.StatusOK, roles)
+}
+
+// getPartners mirrors the cache-manager lookup, PARMA partners are matched by PARMA code and other partners by ID,
+// or by their distributor when distributorId is set.
+func (s *Server) getPartners(w http.ResponseWriter, r *http.Request) {
+	partnerType := r.URL.Query().Get("type")
+	if distributorID := r.URL.Query().Get("distributorId"); distributorID != "" {
+		s.getPartnersByDistributor(w, distributorID, partnerType)
+		return
+	}
+
+	codes := make(map[string]struct{})
+	for _, code := range strings.Split(r.URL.Query().Get("codes"), ",") {
+		codes[code] = struct{}{}
+	}
+
+	partners := make([]*cachemanager.Partner, 0)
+	for _, partner := range s.fixtures.Partners {
+		if partner.Type != partnerType {
+			continue
+		}
+
+		p := partner.Partner
+		if _, ok := codes[cachemanager.PartnerCode(&p, partnerType)]; ok {
+			partners = append(partners, &p)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, cachemanager.PartnersResponse{Data: partners})
+}
+
+func (s *Server) getPartnersByDistributor(w http.ResponseWriter, distributorID, partnerType string) {
+	partners := make([]*cachemanager.Partner, 0)
+	for _, partner := range s.fixtures.Partners {
+		if partner.Type == partnerType && partner.DistributorID == distributorID {
+			p := partner.Partner
+			partners = append(partners, &p)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, cachemanager.PartnersResponse{Data: partners})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
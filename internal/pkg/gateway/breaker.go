@@ -0,0 +1,132 @@
+package gateway
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+func (s BreakerState) String() string {
+	return string(s)
+}
+
+// Breaker is a circuit breaker guarding a single upstream dependency. It opens after FailureThreshold
+// consecutive failures, rejects calls while open and lets HalfOpenRequests trial calls through after OpenTimeout.
+type Breaker struct {
+	dependency string
+	cfg        BreakerConfig
+	now        func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+func NewBreaker(dependency string, cfg BreakerConfig) *Breaker {
+	return &Breaker{
+		dependency: dependency,
+		cfg:        cfg,
+		now:        time.Now,
+		state:      BreakerClosed,
+	}
+}
+
+// Execute runs fn unless the circuit is open, failures reported by IsFailure are counted against the upstream. Other
+// errors, such as cancellations by the caller or rejected requests, neither count as a failure nor as a success.
+func (b *Breaker) Execute(fn func() error) error {
+	if !b.allow() {
+		return NewError(b.dependency, ErrUpstreamUnavailable, 0, ErrCircuitOpen)
+	}
+
+	err := fn()
+	b.record(err)
+
+	return err
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cfg.OpenTimeout {
+			return false
+		}
+
+		b.transition(BreakerHalfOpen)
+		fallthrough
+	case BreakerHalfOpen:
+		if b.trials >= b.cfg.HalfOpenRequests {
+			return false
+		}
+
+		b.trials++
+	}
+
+	return true
+}
+
+// record counts the outcome of a call, a call that neither succeeded nor failed only releases its half-open trial.
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen && b.trials > 0 {
+		b.trials--
+	}
+
+	if err != nil && !IsFailure(err) {
+		return
+	}
+
+	if err == nil {
+		b.failures = 0
+		if b.state == BreakerHalfOpen {
+			b.transition(BreakerClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.openedAt = b.now()
+		b.transition(BreakerOpen)
+	}
+}
+
+func (b *Breaker) transition(state BreakerState) {
+	if b.state == state {
+		return
+	}
+
+	slog.Warn("circuit breaker state changed",
+		slog.String("dependency", b.dependency),
+		slog.String("from", b.state.String()),
+		slog.String("to", state.String()),
+	)
+
+	b.state = state
+	b.trials = 0
+}
@@ -0,0 +1,113 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errFailure  = NewError("upstream", ErrUpstreamUnavailable, 503, nil)
+	errRejected = NewError("upstream", ErrNotFound, 404, nil)
+)
+
+func newBreaker() (*Breaker, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewBreaker("upstream", BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute, HalfOpenRequests: 1})
+	breaker.now = func() time.Time { return now }
+
+	return breaker, &now
+}
+
+func returning(err error) func() error {
+	return func() error { return err }
+}
+
+func TestBreakerStates(t *testing.T) {
+	tests := map[string]struct {
+		calls []error
+		// elapsed is the time passed after the calls
+		elapsed time.Duration
+		state   BreakerState
+	}{
+		"successes":                        {calls: []error{nil, nil}, state: BreakerClosed},
+		"failures below the threshold":     {calls: []error{errFailure}, state: BreakerClosed},
+		"failures at the threshold":        {calls: []error{errFailure, errFailure}, state: BreakerOpen},
+		"success resets the failures":      {calls: []error{errFailure, nil, errFailure}, state: BreakerClosed},
+		"rejected requests are no failure": {calls: []error{errRejected, errRejected}, state: BreakerClosed},
+		"cancellations are no failure":     {calls: []error{context.Canceled, context.Canceled}, state: BreakerClosed},
+		"timeouts are failures":            {calls: []error{NewError("upstream", ErrTimeout, 0, nil), errFailure}, state: BreakerOpen},
+		"open within the timeout":          {calls: []error{errFailure, errFailure}, elapsed: 59 * time.Second, state: BreakerOpen},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			breaker, now := newBreaker()
+			for _, err := range tt.calls {
+				assert.Equal(t, err, breaker.Execute(returning(err)))
+			}
+			*now = now.Add(tt.elapsed)
+
+			assert.Equal(t, tt.state, breaker.State())
+		})
+	}
+}
+
+func TestBreakerRejectsCallsWhileOpen(t *testing.T) {
+	breaker, _ := newBreaker()
+	_ = breaker.Execute(returning(errFailure))
+	_ = breaker.Execute(returning(errFailure))
+
+	called := false
+	err := breaker.Execute(func() error {
+		called = true
+		return nil
+	})
+
+	assert.False(t, called)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+}
+
+func TestBreakerHalfOpenTrial(t *testing.T) {
+	tests := map[string]struct {
+		trial error
+		state BreakerState
+	}{
+		"successful trial closes":         {trial: nil, state: BreakerClosed},
+		"failed trial opens again":        {trial: errFailure, state: BreakerOpen},
+		"cancelled trial stays half-open": {trial: context.Canceled, state: BreakerHalfOpen},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			breaker, now := newBreaker()
+			_ = breaker.Execute(returning(errFailure))
+			_ = breaker.Execute(returning(errFailure))
+			*now = now.Add(time.Minute)
+
+			_ = breaker.Execute(returning(tt.trial))
+			assert.Equal(t, tt.state, breaker.State())
+		})
+	}
+}
+
+func TestBreakerLimitsHalfOpenTrials(t *testing.T) {
+	breaker, now := newBreaker()
+	_ = breaker.Execute(returning(errFailure))
+	_ = breaker.Execute(returning(errFailure))
+	*now = now.Add(time.Minute)
+
+	// a second call is rejected while the trial is running
+	err := breaker.Execute(func() error {
+		assert.ErrorIs(t, breaker.Execute(returning(nil)), ErrCircuitOpen)
+		return context.Canceled
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// the cancelled trial released its slot for the next one
+	assert.NoError(t, breaker.Execute(returning(nil)))
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
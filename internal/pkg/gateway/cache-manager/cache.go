@@ -3,23 +3,23 @@ package cachemanager
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway"
 	"github.com/volvo-cars/go-request/v2"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/sync/errgroup"
 )
 
-const (
-	serviceName    = "cache-manager"
-	attempts       = 3
-	requestTimeout = 5 * time.Second
-)
+const serviceName = "cache-manager"
 
 type PartnerType string
 
@@ -52,9 +52,10 @@ type tracer interface {
 }
 
 type CacheGateway struct {
-	cfg    *Config
-	tracer tracer
-	client requester
+	cfg     *Config
+	tracer  tracer
+	client  requester
+	breaker *gateway.Breaker
 }
 
 func New(cfg *Config, collector collector) *CacheGateway {
@@ -66,12 +67,13 @@ func New(cfg *Config, collector collector) *CacheGateway {
 	}
 
 	return &CacheGateway{
-		cfg:    cfg,
-		tracer: otel.Tracer("gateway/cache"),
+		cfg:     cfg,
+		tracer:  otel.Tracer("gateway/cache"),
+		breaker: gateway.NewBreaker(serviceName, cfg.Breaker),
 		client: request.NewRequestHandler(
 			request.WithHTTPClient(request.NewPooledOAuth2ClientWithOtel(credentials, request.WithServiceAttribute(serviceName))),
-			request.WithAttempts(attempts),
-			request.WithTimeout(requestTimeout),
+			request.WithAttempts(cfg.Request.Attempts),
+			request.WithTimeout(cfg.Request.Timeout),
 			request.OnResponse(func(req *http.Request, res *http.Response, operation string, duration time.Duration) {
 				collector.ObserveRequestTimeWithOp(serviceName, operation, req.Method, req.URL.Path, res.StatusCode, duration)
 			}),
@@ -79,41 +81,179 @@ func New(cfg *Config, collector collector) *CacheGateway {
 	}
 }
 
+// GetPartnersByCodes looks up partners in batches of BatchSize codes, fetched with at most MaxConcurrency requests in flight.
+// Codes that cache-manager did not return are reported, the partners that were found are still returned.
 func (g *CacheGateway) GetPartnersByCodes(ctx context.Context, partnerCodes []string, partnerType string) ([]*Partner, error) {
+	ctx, span := g.tracer.Start(ctx, "cache.GetPartnersByCodes", trace.WithAttributes(attribute.Int("partnerCodes", len(partnerCodes)), attribute.String("partnerType", partnerType)))
+	defer span.End()
+
+	batches := chunk(partnerCodes, g.cfg.BatchSize)
+	if len(batches) == 1 {
+		partners, err := g.getPartnersBatch(ctx, batches[0], partnerType)
+		if err != nil {
+			return nil, err
+		}
+
+		g.reportMissing(ctx, partnerCodes, partners, partnerType)
+		return partners, nil
+	}
+
+	// The first failing batch cancels the others and no further batches are started, only its error is returned.
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(max(g.cfg.MaxConcurrency, 1))
+
+	var (
+		mu       sync.Mutex
+		partners = make([]*Partner, 0, len(partnerCodes))
+	)
+
+	for _, batch := range batches {
+		if groupCtx.Err() != nil {
+			break
+		}
+
+		group.Go(func() error {
+			batchPartners, err := g.getPartnersBatch(groupCtx, batch, partnerType)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			partners = append(partners, batchPartners...)
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	g.reportMissing(ctx, partnerCodes, partners, partnerType)
+	return partners, nil
+}
+
+func (g *CacheGateway) getPartnersBatch(ctx context.Context, partnerCodes []string, partnerType string) ([]*Partner, error) {
 	codes := strings.Join(partnerCodes, ",")
-	operationName := "cache.GetPartnersByCodes"
-	ctx, span := g.tracer.Start(ctx, operationName, trace.WithAttributes(attribute.String("partnerCode", codes), attribute.String("partnerType", partnerType)))
+	ctx, span := g.tracer.Start(ctx, "cache.GetPartnersBatch", trace.WithAttributes(attribute.String("partnerCode", codes), attribute.String("partnerType", partnerType)))
+	defer span.End()
+
+	query := url.Values{}
+	query.Add("codes", codes)
+	query.Add("type", toPartnerType(partnerType))
+
+	return g.getPartners(ctx, query, "cache.GetPartnersByCodes")
+}
+
+// GetPartnersByDistributor looks up the partners of a type under a distributor, e.g. the PARMA retailers of an NSC.
+func (g *CacheGateway) GetPartnersByDistributor(ctx context.Context, distributorID string, partnerType string) ([]*Partner, error) {
+	ctx, span := g.tracer.Start(ctx, "cache.GetPartnersByDistributor", trace.WithAttributes(attribute.String("distributorId", distributorID), attribute.String("partnerType", partnerType)))
 	defer span.End()
 
+	query := url.Values{}
+	query.Add("distributorId", distributorID)
+	query.Add("type", toPartnerType(partnerType))
+
+	return g.getPartners(ctx, query, "cache.GetPartnersByDistributor")
+}
+
+func (g *CacheGateway) getPartners(ctx context.Context, query url.Values, operationName string) ([]*Partner, error) {
 	URL, err := url.Parse(g.cfg.BaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse URL: %w", err)
 	}
 
-	query := URL.Query()
-	query.Add("codes", codes)
-	query.Add("type", toPartnerType(partnerType))
-	URL.RawQuery = query.Encode()
+	values := URL.Query()
+	for key, value := range query {
+		values[key] = value
+	}
+	URL.RawQuery = values.Encode()
 
 	opts := []request.RequestOption{
 		request.WithOperation(operationName),
 	}
 
-	resp, err := g.client.Get(ctx, URL.String(), nil, opts...)
+	var partners []*Partner
+	err = g.breaker.Execute(func() error {
+		resp, err := g.client.Get(ctx, URL.String(), nil, opts...)
+		if err != nil {
+			return gateway.FromTransport(serviceName, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return gateway.FromStatus(serviceName, resp.StatusCode, errorMessage(resp.Body))
+		}
+
+		obj, err := request.Unmarshal[PartnersResponse](resp.Body)
+		if err != nil {
+			return gateway.Malformed(serviceName, resp.StatusCode, err)
+		}
+
+		partners = obj.Data
+		return nil
+	})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get partners: %w", err)
 	}
 
-	obj, err := request.Unmarshal[PartnersResponse](resp.Body)
-	if err != nil {
-		return nil, err
+	return partners, nil
+}
+
+// reportMissing logs the requested codes that cache-manager did not return.
+func (g *CacheGateway) reportMissing(ctx context.Context, partnerCodes []string, partners []*Partner, partnerType string) {
+	missing := MissingCodes(partnerCodes, partners, partnerType)
+	if len(missing) == 0 {
+		return
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get partner response code:[%d] error message:%s", resp.StatusCode, obj.Error.Message)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.StringSlice("missingPartnerCodes", missing))
+	slog.WarnContext(ctx, "cache-manager did not return all requested partners",
+		slog.String("partnerType", partnerType),
+		slog.Any("missingPartnerCodes", missing),
+	)
+}
+
+// MissingCodes returns the partner codes that have no matching partner.
+func MissingCodes(partnerCodes []string, partners []*Partner, partnerType string) []string {
+	found := make(map[string]struct{}, len(partners))
+	for _, partner := range partners {
+		found[PartnerCode(partner, partnerType)] = struct{}{}
+	}
+
+	var missing []string
+	for _, code := range partnerCodes {
+		if _, ok := found[code]; !ok {
+			missing = append(missing, code)
+		}
+	}
+
+	return missing
+}
+
+// chunk splits codes into batches of at most size codes.
+func chunk(codes []string, size int) [][]string {
+	if size <= 0 || len(codes) <= size {
+		return [][]string{codes}
+	}
+
+	batches := make([][]string, 0, (len(codes)+size-1)/size)
+	for start := 0; start < len(codes); start += size {
+		end := min(start+size, len(codes))
+		batches = append(batches, codes[start:end])
+	}
+
+	return batches
+}
+
+// errorMessage extracts the message of an error response, falling back to the raw body.
+func errorMessage(body []byte) string {
+	obj, err := request.Unmarshal[PartnersResponse](body)
+	if err != nil || obj.Error == nil {
+		return string(body)
 	}
 
-	return obj.Data, nil
+	return obj.Error.Message
 }
 
 func toPartnerType(typ string) string {
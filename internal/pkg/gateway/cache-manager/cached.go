@@ -0,0 +1,168 @@
+package cachemanager
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/lru"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const partnerKeySeparator = ":"
+
+type partnerClient interface {
+	GetPartnersByCodes(ctx context.Context, partnerCodes []string, partnerType string) ([]*Partner, error)
+	GetPartnersByDistributor(ctx context.Context, distributorID string, partnerType string) ([]*Partner, error)
+}
+
+// cachedPartner is a cache entry, a nil partner marks a code that cache-manager does not know about.
+type cachedPartner struct {
+	partner *Partner
+}
+
+// CachedGateway keeps rarely changing partner metadata in memory in front of cache-manager.
+type CachedGateway struct {
+	cfg    *Config
+	tracer tracer
+	client partnerClient
+	cache  *lru.Cache[string, cachedPartner]
+	// distributors keeps the partners under a distributor, keyed like the partners by type and distributor ID.
+	distributors *lru.Cache[string, []*Partner]
+}
+
+func NewCachedGateway(cfg *Config, client partnerClient) *CachedGateway {
+	return &CachedGateway{
+		cfg:    cfg,
+		tracer: otel.Tracer("gateway/cache"),
+		client: client,
+		cache:  lru.New[string, cachedPartner](cfg.PartnerCacheSize),
+
+		distributors: lru.New[string, []*Partner](cfg.PartnerCacheSize),
+	}
+}
+
+// GetPartnersByCodes serves known partners from the cache and only asks cache-manager for the missing codes.
+func (g *CachedGateway) GetPartnersByCodes(ctx context.Context, partnerCodes []string, partnerType string) ([]*Partner, error) {
+	if !g.cfg.PartnerCacheEnabled {
+		return g.client.GetPartnersByCodes(ctx, partnerCodes, partnerType)
+	}
+
+	ctx, span := g.tracer.Start(ctx, "cache.CachedGetPartnersByCodes", trace.WithAttributes(attribute.String("partnerType", partnerType)))
+	defer span.End()
+
+	partners := make([]*Partner, 0, len(partnerCodes))
+	missing := make([]string, 0, len(partnerCodes))
+	for _, code := range partnerCodes {
+		cached, ok := g.cache.Get(partnerKey(partnerType, code))
+		if !ok {
+			missing = append(missing, code)
+			continue
+		}
+
+		if cached.partner != nil {
+			partners = append(partners, cached.partner)
+		}
+	}
+
+	span.SetAttributes(attribute.Int("cache.hits", len(partnerCodes)-len(missing)), attribute.Int("cache.misses", len(missing)))
+
+	if len(missing) == 0 {
+		return partners, nil
+	}
+
+	fetched, err := g.client.GetPartnersByCodes(ctx, missing, partnerType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, partner := range fetched {
+		g.cache.Set(partnerKey(partnerType, PartnerCode(partner, partnerType)), cachedPartner{partner: partner}, g.cfg.PartnerCacheTTL)
+		partners = append(partners, partner)
+	}
+
+	for _, code := range MissingCodes(missing, fetched, partnerType) {
+		g.cache.Set(partnerKey(partnerType, code), cachedPartner{}, g.cfg.PartnerCacheNegativeTTL)
+	}
+
+	return partners, nil
+}
+
+// GetPartnersByDistributor serves the partners under a distributor from the cache, the fetched partners are cached by
+// their code as well.
+func (g *CachedGateway) GetPartnersByDistributor(ctx context.Context, distributorID string, partnerType string) ([]*Partner, error) {
+	if !g.cfg.PartnerCacheEnabled {
+		return g.client.GetPartnersByDistributor(ctx, distributorID, partnerType)
+	}
+
+	ctx, span := g.tracer.Start(ctx, "cache.CachedGetPartnersByDistributor", trace.WithAttributes(attribute.String("partnerType", partnerType)))
+	defer span.End()
+
+	key := partnerKey(partnerType, distributorID)
+	if partners, ok := g.distributors.Get(key); ok {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		return partners, nil
+	}
+
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+
+	partners, err := g.client.GetPartnersByDistributor(ctx, distributorID, partnerType)
+	if err != nil {
+		return nil, err
+	}
+
+	g.distributors.Set(key, partners, g.cfg.PartnerCacheTTL)
+	for _, partner := range partners {
+		g.cache.Set(partnerKey(partnerType, PartnerCode(partner, partnerType)), cachedPartner{partner: partner}, g.cfg.PartnerCacheTTL)
+	}
+
+	return partners, nil
+}
+
+// Purge removes every cached partner and returns the number of removed entries.
+func (g *CachedGateway) Purge() int {
+	return g.cache.Purge() + g.distributors.Purge()
+}
+
+// PurgePartner removes the cached entries of a partner, matched by its code or ID across all partner types, together
+// with the distributor entries of the partner and the ones it is listed in.
+func (g *CachedGateway) PurgePartner(partnerID string) int {
+	purged := g.cache.DeleteFunc(func(key string, cached cachedPartner) bool {
+		if strings.HasSuffix(key, partnerKeySeparator+partnerID) {
+			return true
+		}
+
+		return cached.partner != nil && matchesPartner(cached.partner, partnerID)
+	})
+
+	purged += g.distributors.DeleteFunc(func(key string, partners []*Partner) bool {
+		if strings.HasSuffix(key, partnerKeySeparator+partnerID) {
+			return true
+		}
+
+		return slices.ContainsFunc(partners, func(partner *Partner) bool {
+			return matchesPartner(partner, partnerID)
+		})
+	})
+
+	return purged
+}
+
+func matchesPartner(partner *Partner, partnerID string) bool {
+	return partner.ID == partnerID || partner.ParmaPartnerCode == partnerID
+}
+
+// PartnerCode returns the code a partner is requested by, PARMA partners are looked up by their PARMA code.
+func PartnerCode(partner *Partner, partnerType string) string {
+	if toPartnerType(partnerType) == PartnerTypeParma.String() {
+		return partner.ParmaPartnerCode
+	}
+
+	return partner.ID
+}
+
+func partnerKey(partnerType, code string) string {
+	return toPartnerType(partnerType) + partnerKeySeparator + code
+}
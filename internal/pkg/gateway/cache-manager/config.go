@@ -1,7 +1,10 @@
 package cachemanager
 
 import (
+	"time"
+
 	env "github.com/caarlos0/env/v11"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway"
 )
 
 type Config struct {
@@ -10,6 +13,17 @@ type Config struct {
 	ClientSecret string   `env:"CACHE_CLIENT_SECRET,required"`
 	TokenURL     string   `env:"CACHE_TOKEN_URL,required"`
 	Scopes       []string `env:"CACHE_SCOPES,required"`
+
+	BatchSize      int `env:"CACHE_BATCH_SIZE" envDefault:"50"`
+	MaxConcurrency int `env:"CACHE_MAX_CONCURRENCY" envDefault:"4"`
+
+	Request gateway.RequestConfig `envPrefix:"CACHE_"`
+	Breaker gateway.BreakerConfig `envPrefix:"CACHE_"`
+
+	PartnerCacheEnabled     bool          `env:"CACHE_PARTNER_CACHE_ENABLED" envDefault:"true"`
+	PartnerCacheSize        int           `env:"CACHE_PARTNER_CACHE_SIZE" envDefault:"10000"`
+	PartnerCacheTTL         time.Duration `env:"CACHE_PARTNER_CACHE_TTL" envDefault:"1h"`
+	PartnerCacheNegativeTTL time.Duration `env:"CACHE_PARTNER_CACHE_NEGATIVE_TTL" envDefault:"5m"`
 }
 
 func LoadConfig() (*Config, error) {
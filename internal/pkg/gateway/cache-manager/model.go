@@ -34,3 +34,10 @@ type Partner struct {
 	RoleCode         string  `json:"roleCode"`
 	Address          Address `json:"address"`
 }
+
+// TypedPartner is a partner together with the partner type it is looked up by, the form partners are listed in by
+// the static identity source and the fake upstreams.
+type TypedPartner struct {
+	Type string `json:"type"`
+	Partner
+}
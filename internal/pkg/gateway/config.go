@@ -0,0 +1,16 @@
+package gateway
+
+import "time"
+
+// RequestConfig configures the outgoing requests of a gateway, embed it with the dependency env prefix.
+type RequestConfig struct {
+	Attempts int           `env:"REQUEST_ATTEMPTS" envDefault:"3"`
+	Timeout  time.Duration `env:"REQUEST_TIMEOUT" envDefault:"5s"`
+}
+
+// BreakerConfig configures the circuit breaker of a gateway, embed it with the dependency env prefix.
+type BreakerConfig struct {
+	FailureThreshold int           `env:"BREAKER_FAILURE_THRESHOLD" envDefault:"5"`
+	OpenTimeout      time.Duration `env:"BREAKER_OPEN_TIMEOUT" envDefault:"30s"`
+	HalfOpenRequests int           `env:"BREAKER_HALF_OPEN_REQUESTS" envDefault:"1"`
+}
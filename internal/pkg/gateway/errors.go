@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrTimeout             = errors.New("timeout")
+	ErrMalformedResponse   = errors.New("malformed response")
+)
+
+// Error is an upstream failure classified into one of the gateway error kinds,
+// match it with errors.Is against the kind, e.g. errors.Is(err, gateway.ErrTimeout).
+type Error struct {
+	Dependency string
+	Kind       error
+	StatusCode int
+	Err        error
+}
+
+func NewError(dependency string, kind error, statusCode int, err error) *Error {
+	return &Error{
+		Dependency: dependency,
+		Kind:       kind,
+		StatusCode: statusCode,
+		Err:        err,
+	}
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Dependency + ": " + e.Kind.Error())
+
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, " (status %d)", e.StatusCode)
+	}
+
+	if e.Err != nil {
+		b.WriteString(": " + e.Err.Error())
+	}
+
+	return b.String()
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+// FromStatus classifies an unexpected upstream response status.
+func FromStatus(dependency string, statusCode int, message string) error {
+	var err error
+	if message != "" {
+		err = errors.New(message)
+	}
+
+	switch {
+	case statusCode == http.StatusNotFound:
+		return NewError(dependency, ErrNotFound, statusCode, err)
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return NewError(dependency, ErrUnauthorized, statusCode, err)
+	case statusCode == http.StatusRequestTimeout, statusCode == http.StatusGatewayTimeout:
+		return NewError(dependency, ErrTimeout, statusCode, err)
+	default:
+		return NewError(dependency, ErrUpstreamUnavailable, statusCode, err)
+	}
+}
+
+// FromTransport classifies an error returned before any upstream response was received.
+// Cancellations by the caller are returned unchanged, they say nothing about the upstream health.
+func FromTransport(dependency string, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return NewError(dependency, ErrUnauthorized, 0, err)
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return NewError(dependency, ErrTimeout, 0, err)
+	}
+
+	return NewError(dependency, ErrUpstreamUnavailable, 0, err)
+}
+
+// Malformed classifies a response body that could not be decoded.
+func Malformed(dependency string, statusCode int, err error) error {
+	return NewError(dependency, ErrMalformedResponse, statusCode, err)
+}
+
+// IsFailure reports whether err tells that the upstream is unhealthy.
+func IsFailure(err error) bool {
+	return errors.Is(err, ErrUpstreamUnavailable) || errors.Is(err, ErrTimeout)
+}
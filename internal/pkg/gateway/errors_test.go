@@ -0,0 +1,77 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestError(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := NewError("plums", ErrUpstreamUnavailable, http.StatusBadGateway, cause)
+
+	assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, ErrTimeout)
+	assert.Equal(t, "plums: upstream unavailable (status 502): connection refused", err.Error())
+	assert.Equal(t, "plums: not found", NewError("plums", ErrNotFound, 0, nil).Error())
+}
+
+func TestFromStatus(t *testing.T) {
+	tests := map[int]error{
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusForbidden:           ErrUnauthorized,
+		http.StatusRequestTimeout:      ErrTimeout,
+		http.StatusGatewayTimeout:      ErrTimeout,
+		http.StatusBadRequest:          ErrUpstreamUnavailable,
+		http.StatusInternalServerError: ErrUpstreamUnavailable,
+		http.StatusServiceUnavailable:  ErrUpstreamUnavailable,
+	}
+
+	for status, kind := range tests {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			err := FromStatus("plums", status, "upstream message")
+			assert.ErrorIs(t, err, kind)
+			assert.Contains(t, err.Error(), "upstream message")
+		})
+	}
+}
+
+func TestFromTransport(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		kind error
+	}{
+		"deadline":        {err: fmt.Errorf("get: %w", context.DeadlineExceeded), kind: ErrTimeout},
+		"network timeout": {err: fmt.Errorf("dial: %w", os.ErrDeadlineExceeded), kind: ErrTimeout},
+		"token endpoint":  {err: &oauth2.RetrieveError{}, kind: ErrUnauthorized},
+		"other":           {err: errors.New("connection refused"), kind: ErrUpstreamUnavailable},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := FromTransport("plums", tt.err)
+			assert.ErrorIs(t, err, tt.kind)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	// cancellations by the caller say nothing about the upstream
+	cancelled := fmt.Errorf("get: %w", context.Canceled)
+	assert.Equal(t, cancelled, FromTransport("plums", cancelled))
+	assert.False(t, IsFailure(cancelled))
+}
+
+func TestIsFailure(t *testing.T) {
+	assert.True(t, IsFailure(NewError("plums", ErrUpstreamUnavailable, 0, nil)))
+	assert.True(t, IsFailure(NewError("plums", ErrTimeout, 0, nil)))
+	assert.False(t, IsFailure(NewError("plums", ErrNotFound, http.StatusNotFound, nil)))
+	assert.False(t, IsFailure(Malformed("plums", http.StatusOK, errors.New("unexpected EOF"))))
+}
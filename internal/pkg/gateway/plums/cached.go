@@ -0,0 +1,202 @@
+package plums
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/lru"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const refreshTimeout = 10 * time.Second
+
+type userClient interface {
+	GetUser(ctx context.Context, lookup Lookup) (*User, error)
+}
+
+type cachedUser struct {
+	User      *User     `json:"user"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// CachedGateway serves PLUMS users from memory, stale entries are served within the grace period
+// while they are refreshed in the background, so that a slow or unavailable PLUMS does not fail authorization.
+type CachedGateway struct {
+	cfg        *Config
+	tracer     tracer
+	client     userClient
+	cache      *lru.Cache[string, cachedUser]
+	refreshing sync.Map
+}
+
+func NewCachedGateway(cfg *Config, client userClient) *CachedGateway {
+	return &CachedGateway{
+		cfg:    cfg,
+		tracer: otel.Tracer("gateway/plums"),
+		client: client,
+		cache:  lru.New[string, cachedUser](cfg.UserCacheSize),
+	}
+}
+
+// GetUser caches every lookup under its own key, a user looked up by CDSID and by email is cached twice.
+func (g *CachedGateway) GetUser(ctx context.Context, lookup Lookup) (*User, error) {
+	if !g.cfg.UserCacheEnabled {
+		return g.client.GetUser(ctx, lookup)
+	}
+
+	ctx, span := g.tracer.Start(ctx, "plums.CachedGetUser", trace.WithAttributes(attribute.String("lookup", lookup.Key())))
+	defer span.End()
+
+	key := lookup.Key()
+	if cached, ok := g.cache.Get(key); ok {
+		if time.Since(cached.FetchedAt) < g.cfg.UserCacheTTL {
+			span.SetAttributes(attribute.String("cache.result", "hit"))
+			return cached.user(false), nil
+		}
+
+		span.SetAttributes(attribute.String("cache.result", "stale"))
+		g.refresh(ctx, lookup)
+		return cached.user(true), nil
+	}
+
+	span.SetAttributes(attribute.String("cache.result", "miss"))
+	return g.fetch(ctx, lookup)
+}
+
+func (g *CachedGateway) fetch(ctx context.Context, lookup Lookup) (*User, error) {
+	user, err := g.client.GetUser(ctx, lookup)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			g.cache.Delete(lookup.Key())
+		}
+		return nil, err
+	}
+
+	entry := cachedUser{User: user, FetchedAt: time.Now()}
+	g.store(lookup.Key(), entry)
+	return entry.user(false), nil
+}
+
+// refresh re-fetches a stale user in the background, concurrent refreshes of the same user are collapsed.
+func (g *CachedGateway) refresh(ctx context.Context, lookup Lookup) {
+	key := lookup.Key()
+	if _, loaded := g.refreshing.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	go func() {
+		defer g.refreshing.Delete(key)
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+		defer cancel()
+
+		if _, err := g.fetch(ctx, lookup); err != nil {
+			slog.Warn("failed to refresh stale plums user", slog.String("lookup", key), slog.Any("error", err))
+		}
+	}()
+}
+
+func (g *CachedGateway) store(key string, entry cachedUser) {
+	ttl := g.cfg.UserCacheTTL + g.cfg.UserCacheGracePeriod - time.Since(entry.FetchedAt)
+	if ttl <= 0 {
+		return
+	}
+
+	g.cache.Set(key, entry, ttl)
+}
+
+// Load restores the users persisted by Save, entries that are past their grace period are skipped.
+func (g *CachedGateway) Load() error {
+	if g.cfg.UserCacheFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(g.cfg.UserCacheFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read plums user cache file [%s]: %w", g.cfg.UserCacheFile, err)
+	}
+
+	var entries map[string]cachedUser
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to unmarshal plums user cache file [%s]: %w", g.cfg.UserCacheFile, err)
+	}
+
+	for key, entry := range entries {
+		if entry.User == nil {
+			continue
+		}
+		g.store(key, entry)
+	}
+
+	return nil
+}
+
+// Save writes the cached users to the configured file, the file is replaced atomically.
+func (g *CachedGateway) Save() error {
+	if g.cfg.UserCacheFile == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(g.cache.List())
+	if err != nil {
+		return fmt.Errorf("failed to marshal plums user cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(g.cfg.UserCacheFile), filepath.Base(g.cfg.UserCacheFile)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create plums user cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write plums user cache file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write plums user cache file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), g.cfg.UserCacheFile)
+}
+
+// Persist periodically saves the cache until ctx is done.
+func (g *CachedGateway) Persist(ctx context.Context) {
+	if g.cfg.UserCacheFile == "" {
+		return
+	}
+
+	ticker := time.NewTicker(g.cfg.UserCacheFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := g.Save(); err != nil {
+				slog.Error("failed to persist plums user cache", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// user returns a copy of the cached user, so that callers never share the cached instance.
+func (c cachedUser) user(stale bool) *User {
+	user := *c.User
+	user.Stale = stale
+	user.FetchedAt = c.FetchedAt
+	return &user
+}
@@ -1,7 +1,10 @@
 package plums
 
 import (
+	"time"
+
 	env "github.com/caarlos0/env/v11"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway"
 )
 
 type Config struct {
@@ -13,6 +16,16 @@ type Config struct {
 	UserKey      string   `env:"PLUMS_USER_KEY,required"`
 	Audience     string   `env:"PLUMS_AUDIENCE,required"`
 	Scopes       []string `env:"PLUMS_SCOPES,required"`
+
+	Request gateway.RequestConfig `envPrefix:"PLUMS_"`
+	Breaker gateway.BreakerConfig `envPrefix:"PLUMS_"`
+
+	UserCacheEnabled       bool          `env:"PLUMS_USER_CACHE_ENABLED" envDefault:"true"`
+	UserCacheSize          int           `env:"PLUMS_USER_CACHE_SIZE" envDefault:"10000"`
+	UserCacheTTL           time.Duration `env:"PLUMS_USER_CACHE_TTL" envDefault:"1m"`
+	UserCacheGracePeriod   time.Duration `env:"PLUMS_USER_CACHE_GRACE_PERIOD" envDefault:"30m"`
+	UserCacheFile          string        `env:"PLUMS_USER_CACHE_FILE"`
+	UserCacheFlushInterval time.Duration `env:"PLUMS_USER_CACHE_FLUSH_INTERVAL" envDefault:"1m"`
 }
 
 func LoadConfig() (*Config, error) {
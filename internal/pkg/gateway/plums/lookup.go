@@ -0,0 +1,87 @@
+package plums
+
+import (
+	"strings"
+)
+
+// LookupKind is the user attribute a Lookup matches on.
+type LookupKind string
+
+const (
+	LookupCDSID    LookupKind = "cdsid"
+	LookupEmail    LookupKind = "email"
+	LookupUserID   LookupKind = "user_id"
+	LookupIdentity LookupKind = "identity"
+)
+
+func (k LookupKind) String() string {
+	return string(k)
+}
+
+// Lookup selects a single user, Provider is only set for identity lookups where Value is the provider user ID.
+type Lookup struct {
+	Kind     LookupKind
+	Value    string
+	Provider string
+}
+
+func ByCDSID(cdsid string) Lookup {
+	return Lookup{Kind: LookupCDSID, Value: cdsid}
+}
+
+func ByEmail(email string) Lookup {
+	return Lookup{Kind: LookupEmail, Value: email}
+}
+
+func ByUserID(userID string) Lookup {
+	return Lookup{Kind: LookupUserID, Value: userID}
+}
+
+func ByIdentity(provider, providerUserID string) Lookup {
+	return Lookup{Kind: LookupIdentity, Value: providerUserID, Provider: provider}
+}
+
+// Valid reports whether the values of the lookup are safe to send to PLUMS, where CDSIDs and user IDs are path
+// segments. Values with slashes, backslashes or .. are rejected.
+func (l Lookup) Valid() bool {
+	for _, value := range []string{l.Value, l.Provider} {
+		if strings.ContainsAny(value, `/\`) || strings.Contains(value, "..") {
+			return false
+		}
+	}
+
+	return l.Value != ""
+}
+
+// Key identifies the lookup, all user keys are compared case-insensitively.
+func (l Lookup) Key() string {
+	if l.Kind == LookupIdentity {
+		return strings.ToLower(l.Kind.String() + ":" + l.Provider + "/" + l.Value)
+	}
+
+	return strings.ToLower(l.Kind.String() + ":" + l.Value)
+}
+
+func (l Lookup) String() string {
+	return l.Key()
+}
+
+// Matches reports whether user is the user selected by the lookup.
+func (l Lookup) Matches(user *User) bool {
+	switch l.Kind {
+	case LookupCDSID:
+		return strings.EqualFold(user.CDSID, l.Value)
+	case LookupEmail:
+		return strings.EqualFold(user.Email, l.Value)
+	case LookupUserID:
+		return strings.EqualFold(user.UserID, l.Value)
+	case LookupIdentity:
+		for _, identity := range user.UserIdentities {
+			if strings.EqualFold(identity.Provider, l.Provider) && strings.EqualFold(identity.ProviderUserID, l.Value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
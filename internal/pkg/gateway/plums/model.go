@@ -1,5 +1,7 @@
 package plums
 
+import "time"
+
 type User struct {
 	UserID         string         `json:"userId"`
 	FirstName      string         `json:"firstName"`
@@ -9,6 +11,10 @@ type User struct {
 	CountryCode    string         `json:"countryCode"`
 	Partners       []Partner      `json:"partners"`
 	UserIdentities []UserIdentity `json:"userIdentities"`
+
+	// Stale is set when the user is served from the cache past its TTL, FetchedAt is when PLUMS returned it.
+	Stale     bool      `json:"-"`
+	FetchedAt time.Time `json:"-"`
 }
 
 type Partner struct {
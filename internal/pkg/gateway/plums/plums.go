@@ -6,9 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"path"
 	"time"
 
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway"
 	"github.com/volvo-cars/go-request/v2"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -17,11 +17,10 @@ import (
 )
 
 const (
-	serviceName    = "plums"
-	usersPath      = "users/by-cdsid"
-	rolesPath      = "roles"
-	attempts       = 3
-	requestTimeout = 5 * time.Second
+	serviceName      = "plums"
+	usersPath        = "users"
+	usersByCDSIDPath = "users/by-cdsid"
+	rolesPath        = "roles"
 )
 
 const (
@@ -30,7 +29,10 @@ const (
 	unableToGetRolesFromPlumsMessage = "unable to get roles from plums: %w"
 )
 
-var ErrUserNotFound = errors.New("plums: user not found")
+var (
+	ErrUserNotFound  = errors.New("user not found")
+	ErrInvalidLookup = errors.New("user lookup is invalid")
+)
 
 type collector interface {
 	ObserveRequestTimeWithOp(dependency, operation, method, route string, status int, duration time.Duration)
@@ -48,6 +50,7 @@ type PlumsGateway struct {
 	cfg           *Config
 	tracer        tracer
 	client        requester
+	breaker       *gateway.Breaker
 	userKeyHeader map[string]string
 }
 
@@ -63,15 +66,16 @@ func New(cfg *Config, collector collector) *PlumsGateway {
 	}
 
 	return &PlumsGateway{
-		cfg:    cfg,
-		tracer: otel.Tracer("gateway/plums"),
+		cfg:     cfg,
+		tracer:  otel.Tracer("gateway/plums"),
+		breaker: gateway.NewBreaker(serviceName, cfg.Breaker),
 		userKeyHeader: map[string]string{
 			"user-key": cfg.UserKey,
 		},
 		client: request.NewRequestHandler(
 			request.WithHTTPClient(request.NewPooledOAuth2ClientWithOtel(credentials, request.WithServiceAttribute(serviceName))),
-			request.WithAttempts(attempts),
-			request.WithTimeout(requestTimeout),
+			request.WithAttempts(cfg.Request.Attempts),
+			request.WithTimeout(cfg.Request.Timeout),
 			request.OnResponse(func(req *http.Request, res *http.Response, operation string, duration time.Duration) {
 				collector.ObserveRequestTimeWithOp(serviceName, operation, req.Method, req.URL.Path, res.StatusCode, duration)
 			}),
@@ -79,49 +83,107 @@ func New(cfg *Config, collector collector) *PlumsGateway {
 	}
 }
 
-func (g *PlumsGateway) GetUserByCDSID(ctx context.Context, cdsid string) (*User, error) {
-	ctx, span := g.tracer.Start(ctx, "plums.GetUserByCDSID", trace.WithAttributes(attribute.String("user_cdsid", cdsid)))
+// GetUser looks up a single user, CDSIDs and user IDs are fetched directly and emails and identities are searched for.
+func (g *PlumsGateway) GetUser(ctx context.Context, lookup Lookup) (*User, error) {
+	ctx, span := g.tracer.Start(ctx, "plums.GetUser", trace.WithAttributes(attribute.String("lookup_kind", lookup.Kind.String()), attribute.String("lookup_value", lookup.Value)))
 	defer span.End()
 
+	if !lookup.Valid() {
+		return nil, fmt.Errorf("%w: [%s]", ErrInvalidLookup, lookup)
+	}
+
 	u, err := url.Parse(g.cfg.BaseURL)
 	if err != nil {
 		return nil, fmt.Errorf(unableToParseBaseURLMessage, err)
 	}
 
-	u.Path = path.Join(u.Path, usersPath, cdsid)
+	query := u.Query()
+	switch lookup.Kind {
+	case LookupCDSID:
+		u = u.JoinPath(usersByCDSIDPath, url.PathEscape(lookup.Value))
+	case LookupUserID:
+		u = u.JoinPath(usersPath, url.PathEscape(lookup.Value))
+	case LookupEmail:
+		u = u.JoinPath(usersPath)
+		query.Set("email", lookup.Value)
+	case LookupIdentity:
+		u = u.JoinPath(usersPath)
+		query.Set("provider", lookup.Provider)
+		query.Set("providerUserId", lookup.Value)
+	default:
+		return nil, fmt.Errorf("unsupported user lookup [%s]", lookup.Kind)
+	}
+	u.RawQuery = query.Encode()
 
-	response, err := g.client.Get(ctx, u.String(), g.userKeyHeader)
+	response, err := g.get(ctx, u.String())
 	if err != nil {
+		if errors.Is(err, gateway.ErrNotFound) {
+			return nil, gateway.NewError(serviceName, gateway.ErrNotFound, http.StatusNotFound, ErrUserNotFound)
+		}
 		return nil, fmt.Errorf(unableToGetUserFromPlumsMessage, err)
 	}
 
-	if response.StatusCode == http.StatusNotFound {
-		return nil, ErrUserNotFound
+	if lookup.Kind == LookupCDSID || lookup.Kind == LookupUserID {
+		user, err := request.Unmarshal[*User](response.Body)
+		if err != nil {
+			return nil, fmt.Errorf(unableToGetUserFromPlumsMessage, gateway.Malformed(serviceName, response.StatusCode, err))
+		}
+
+		return user, nil
+	}
+
+	users, err := request.Unmarshal[Users](response.Body)
+	if err != nil {
+		return nil, fmt.Errorf(unableToGetUserFromPlumsMessage, gateway.Malformed(serviceName, response.StatusCode, err))
 	}
 
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get user from plums: %s", response.Body)
+	// search results are matched again, so that a broad search never resolves to another user
+	for _, user := range users.Result {
+		if user != nil && lookup.Matches(user) {
+			return user, nil
+		}
 	}
 
-	return request.Unmarshal[*User](response.Body)
+	return nil, gateway.NewError(serviceName, gateway.ErrNotFound, http.StatusNotFound, ErrUserNotFound)
 }
 
-func (g *PlumsGateway) GetRoles(ctx context.Context) (*[]Role, error) {
+func (g *PlumsGateway) GetRoles(ctx context.Context) ([]Role, error) {
 	u, err := url.Parse(g.cfg.BaseURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse BaseUrl: %w", err)
+		return nil, fmt.Errorf(unableToParseBaseURLMessage, err)
 	}
 
-	u.Path = path.Join(u.Path, rolesPath)
+	u = u.JoinPath(rolesPath)
 
-	response, err := g.client.Get(ctx, u.String(), g.userKeyHeader)
+	response, err := g.get(ctx, u.String())
 	if err != nil {
 		return nil, fmt.Errorf(unableToGetRolesFromPlumsMessage, err)
 	}
 
-	if response.StatusCode == http.StatusNotFound {
-		return nil, ErrUserNotFound
+	roles, err := request.Unmarshal[[]Role](response.Body)
+	if err != nil {
+		return nil, fmt.Errorf(unableToGetRolesFromPlumsMessage, gateway.Malformed(serviceName, response.StatusCode, err))
 	}
 
-	return request.Unmarshal[*[]Role](response.Body)
+	return roles, nil
+}
+
+// get performs the request through the circuit breaker, any response but 200 is returned as a classified gateway error.
+func (g *PlumsGateway) get(ctx context.Context, url string) (*request.HTTPResponse, error) {
+	var response *request.HTTPResponse
+	err := g.breaker.Execute(func() error {
+		res, err := g.client.Get(ctx, url, g.userKeyHeader)
+		if err != nil {
+			return gateway.FromTransport(serviceName, err)
+		}
+
+		if res.StatusCode != http.StatusOK {
+			return gateway.FromStatus(serviceName, res.StatusCode, string(res.Body))
+		}
+
+		response = res
+		return nil
+	})
+
+	return response, err
 }
@@ -0,0 +1,149 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	cachemanager "github.com/volvo-cars/connect-access-control/internal/pkg/gateway/cache-manager"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type UserSource interface {
+	GetUser(ctx context.Context, lookup plums.Lookup) (*plums.User, error)
+}
+
+type PartnerSource interface {
+	GetPartnersByCodes(ctx context.Context, partnerCodes []string, partnerType string) ([]*cachemanager.Partner, error)
+	GetPartnersByDistributor(ctx context.Context, distributorID string, partnerType string) ([]*cachemanager.Partner, error)
+}
+
+// CompositeUserSource tries its sources in order and returns the first user found.
+type CompositeUserSource struct {
+	tracer  tracer
+	sources []UserSource
+}
+
+func NewCompositeUserSource(sources ...UserSource) *CompositeUserSource {
+	return &CompositeUserSource{
+		tracer:  otel.Tracer("identity/composite"),
+		sources: sources,
+	}
+}
+
+// GetUser returns plums.ErrUserNotFound only when no source knows the user, when a source failed
+// and none of the others found the user the failures are returned instead, as the user may still exist.
+func (c *CompositeUserSource) GetUser(ctx context.Context, lookup plums.Lookup) (*plums.User, error) {
+	ctx, span := c.tracer.Start(ctx, "composite.GetUser", trace.WithAttributes(attribute.String("lookup", lookup.Key())))
+	defer span.End()
+
+	var errs error
+	for _, source := range c.sources {
+		user, err := source.GetUser(ctx, lookup)
+		if err == nil {
+			return user, nil
+		}
+
+		if !errors.Is(err, plums.ErrUserNotFound) {
+			slog.WarnContext(ctx, "identity source failed to get user", slog.String("lookup", lookup.Key()), slog.Any("error", err))
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	if errs != nil {
+		return nil, errs
+	}
+
+	return nil, plums.ErrUserNotFound
+}
+
+// CompositePartnerSource tries its sources in order, each source is only asked for the codes the previous ones did not return.
+type CompositePartnerSource struct {
+	tracer  tracer
+	sources []PartnerSource
+}
+
+func NewCompositePartnerSource(sources ...PartnerSource) *CompositePartnerSource {
+	return &CompositePartnerSource{
+		tracer:  otel.Tracer("identity/composite"),
+		sources: sources,
+	}
+}
+
+// GetPartnersByCodes returns the partners found by any source, an error is only returned when every source failed.
+func (c *CompositePartnerSource) GetPartnersByCodes(ctx context.Context, partnerCodes []string, partnerType string) ([]*cachemanager.Partner, error) {
+	ctx, span := c.tracer.Start(ctx, "composite.GetPartnersByCodes", trace.WithAttributes(attribute.Int("partnerCodes", len(partnerCodes)), attribute.String("partnerType", partnerType)))
+	defer span.End()
+
+	var (
+		errs      error
+		succeeded bool
+		partners  = make([]*cachemanager.Partner, 0, len(partnerCodes))
+		missing   = partnerCodes
+	)
+
+	for _, source := range c.sources {
+		if len(missing) == 0 {
+			break
+		}
+
+		found, err := source.GetPartnersByCodes(ctx, missing, partnerType)
+		if err != nil {
+			slog.WarnContext(ctx, "identity source failed to get partners", slog.String("partnerType", partnerType), slog.Any("error", err))
+			errs = errors.Join(errs, err)
+			continue
+		}
+
+		succeeded = true
+		partners = append(partners, found...)
+		missing = cachemanager.MissingCodes(missing, found, partnerType)
+	}
+
+	if !succeeded && errs != nil {
+		return nil, errs
+	}
+
+	return partners, nil
+}
+
+// GetPartnersByDistributor merges the partners of every source, a partner found by several sources is returned once,
+// as found by the first of them. An error is only returned when every source failed.
+func (c *CompositePartnerSource) GetPartnersByDistributor(ctx context.Context, distributorID string, partnerType string) ([]*cachemanager.Partner, error) {
+	ctx, span := c.tracer.Start(ctx, "composite.GetPartnersByDistributor", trace.WithAttributes(attribute.String("distributorId", distributorID), attribute.String("partnerType", partnerType)))
+	defer span.End()
+
+	var (
+		errs      error
+		succeeded bool
+		partners  []*cachemanager.Partner
+		seen      = make(map[string]struct{})
+	)
+
+	for _, source := range c.sources {
+		found, err := source.GetPartnersByDistributor(ctx, distributorID, partnerType)
+		if err != nil {
+			slog.WarnContext(ctx, "identity source failed to get partners by distributor", slog.String("distributorId", distributorID), slog.Any("error", err))
+			errs = errors.Join(errs, err)
+			continue
+		}
+
+		succeeded = true
+		for _, partner := range found {
+			code := cachemanager.PartnerCode(partner, partnerType)
+			if _, ok := seen[code]; ok {
+				continue
+			}
+			seen[code] = struct{}{}
+			partners = append(partners, partner)
+		}
+	}
+
+	if !succeeded && errs != nil {
+		return nil, errs
+	}
+
+	return partners, nil
+}
@@ -0,0 +1,29 @@
+package identity
+
+import (
+	env "github.com/caarlos0/env/v11"
+)
+
+const (
+	// SourcePlums resolves users from PLUMS and partners from cache-manager.
+	SourcePlums = "plums"
+	// SourceStatic resolves users and partners from the YAML files in StaticDir.
+	SourceStatic = "static"
+)
+
+type Config struct {
+	// Sources are tried in order, e.g. "static,plums" serves the static users and falls back to PLUMS for the rest.
+	Sources   []string `env:"IDENTITY_SOURCES" envDefault:"plums"`
+	StaticDir string   `env:"IDENTITY_STATIC_DIR"`
+	// ProvidersFile lists the identity providers the CDSID of a user is extracted from.
+	ProvidersFile string `env:"IDENTITY_PROVIDERS_FILE,expand" envDefault:"${IAM_ROOT_DIR}/config/identity-providers.yaml"`
+}
+
+func LoadConfig() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
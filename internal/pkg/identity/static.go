@@ -0,0 +1,126 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cachemanager "github.com/volvo-cars/connect-access-control/internal/pkg/gateway/cache-manager"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	usersFile    = "users.yaml"
+	partnersFile = "partners.yaml"
+)
+
+type tracer interface {
+	Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span)
+}
+
+type staticUsers struct {
+	Users []plums.User `json:"users"`
+}
+
+type staticPartners struct {
+	Partners []cachemanager.TypedPartner `json:"partners"`
+}
+
+// StaticSource serves users and partners from the users.yaml and partners.yaml files of a directory,
+// users use the PLUMS model and partners the cache-manager model. Either file may be omitted.
+type StaticSource struct {
+	tracer   tracer
+	users    []plums.User
+	partners map[string]map[string]*cachemanager.Partner
+}
+
+func NewStaticSource(dir string) (*StaticSource, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to open static identity directory [%s]: %w", dir, err)
+	}
+
+	users, err := readStatic[staticUsers](filepath.Join(dir, usersFile))
+	if err != nil {
+		return nil, err
+	}
+
+	partners, err := readStatic[staticPartners](filepath.Join(dir, partnersFile))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &StaticSource{
+		tracer:   otel.Tracer("identity/static"),
+		users:    users.Users,
+		partners: make(map[string]map[string]*cachemanager.Partner),
+	}
+
+	for i := range partners.Partners {
+		partner := &partners.Partners[i]
+		if s.partners[partner.Type] == nil {
+			s.partners[partner.Type] = make(map[string]*cachemanager.Partner)
+		}
+		s.partners[partner.Type][cachemanager.PartnerCode(&partner.Partner, partner.Type)] = &partner.Partner
+	}
+
+	return s, nil
+}
+
+func (s *StaticSource) GetUser(ctx context.Context, lookup plums.Lookup) (*plums.User, error) {
+	_, span := s.tracer.Start(ctx, "static.GetUser", trace.WithAttributes(attribute.String("lookup", lookup.Key())))
+	defer span.End()
+
+	for _, user := range s.users {
+		if lookup.Matches(&user) {
+			return &user, nil
+		}
+	}
+
+	return nil, plums.ErrUserNotFound
+}
+
+func (s *StaticSource) GetPartnersByCodes(ctx context.Context, partnerCodes []string, partnerType string) ([]*cachemanager.Partner, error) {
+	_, span := s.tracer.Start(ctx, "static.GetPartnersByCodes", trace.WithAttributes(attribute.Int("partnerCodes", len(partnerCodes)), attribute.String("partnerType", partnerType)))
+	defer span.End()
+
+	partners := make([]*cachemanager.Partner, 0, len(partnerCodes))
+	for _, code := range partnerCodes {
+		if partner, ok := s.partners[partnerType][code]; ok {
+			p := *partner
+			partners = append(partners, &p)
+		}
+	}
+
+	return partners, nil
+}
+
+// GetPartnersByDistributor returns the partners of the type whose distributor is distributorID.
+func (s *StaticSource) GetPartnersByDistributor(ctx context.Context, distributorID string, partnerType string) ([]*cachemanager.Partner, error) {
+	_, span := s.tracer.Start(ctx, "static.GetPartnersByDistributor", trace.WithAttributes(attribute.String("distributorId", distributorID), attribute.String("partnerType", partnerType)))
+	defer span.End()
+
+	partners := make([]*cachemanager.Partner, 0)
+	for _, partner := range s.partners[partnerType] {
+		if partner.DistributorID == distributorID {
+			p := *partner
+			partners = append(partners, &p)
+		}
+	}
+
+	return partners, nil
+}
+
+func readStatic[T any](filePath string) (T, error) {
+	result, err := utils.YAMLUnmarshal[T](filePath)
+	if err != nil && !errors.Is(err, utils.ErrNotFound) {
+		return result, fmt.Errorf("failed to unmarshal static identity file [%s]: %w", filePath, err)
+	}
+
+	return result, nil
+}
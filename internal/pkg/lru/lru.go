@@ -0,0 +1,146 @@
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is a size bounded, thread-safe LRU cache where every entry carries its own expiry.
+type Cache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[K]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+func New[K comparable, V any](capacity int) *Cache[K, V] {
+	return &Cache[K, V]{
+		capacity: capacity,
+		items:    make(map[K]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get returns the value stored for key, expired entries are evicted and reported as missing.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	elem, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := elem.Value.(*entry[K, V])
+	if !e.expiresAt.After(c.now()) {
+		c.remove(elem)
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+	return e.value, true
+}
+
+// Set stores value for key for the given ttl, evicting the least recently used entry when the cache is full.
+func (c *Cache[K, V]) Set(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete removes key from the cache.
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+
+	c.remove(elem)
+	return true
+}
+
+// DeleteFunc removes every entry matched by fn and returns the number of removed entries.
+func (c *Cache[K, V]) DeleteFunc(fn func(K, V) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, elem := range c.items {
+		if fn(key, elem.Value.(*entry[K, V]).value) {
+			c.remove(elem)
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// Purge removes all entries and returns the number of removed entries.
+func (c *Cache[K, V]) Purge() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := len(c.items)
+	c.items = make(map[K]*list.Element)
+	c.order.Init()
+
+	return removed
+}
+
+// List returns all entries that did not expire yet.
+func (c *Cache[K, V]) List() map[K]V {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	m := make(map[K]V, len(c.items))
+	for key, elem := range c.items {
+		e := elem.Value.(*entry[K, V])
+		if e.expiresAt.After(now) {
+			m[key] = e.value
+		}
+	}
+
+	return m
+}
+
+// Len returns the number of entries, including the ones that expired but were not evicted yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *Cache[K, V]) remove(elem *list.Element) {
+	e := elem.Value.(*entry[K, V])
+	delete(c.items, e.key)
+	c.order.Remove(elem)
+}
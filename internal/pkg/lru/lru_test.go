@@ -0,0 +1,117 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clock is a manually advanced time source for the expiry of the entries.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newCache(capacity int) (*Cache[string, int], *clock) {
+	c := &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cache := New[string, int](capacity)
+	cache.now = c.Now
+
+	return cache, c
+}
+
+func TestGet(t *testing.T) {
+	tests := map[string]struct {
+		ttl     time.Duration
+		elapsed time.Duration
+		found   bool
+	}{
+		"before the expiry": {ttl: time.Minute, elapsed: 59 * time.Second, found: true},
+		"at the expiry":     {ttl: time.Minute, elapsed: time.Minute},
+		"after the expiry":  {ttl: time.Minute, elapsed: 2 * time.Minute},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cache, clock := newCache(2)
+			cache.Set("a", 1, tt.ttl)
+			clock.now = clock.now.Add(tt.elapsed)
+
+			value, ok := cache.Get("a")
+			assert.Equal(t, tt.found, ok)
+			if tt.found {
+				assert.Equal(t, 1, value)
+				return
+			}
+
+			// expired entries are evicted when they are read
+			assert.Zero(t, value)
+			assert.Zero(t, cache.Len())
+		})
+	}
+}
+
+func TestSetEvictsLeastRecentlyUsed(t *testing.T) {
+	cache, _ := newCache(2)
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, time.Minute)
+
+	// reading a makes b the least recently used entry
+	_, _ = cache.Get("a")
+	cache.Set("c", 3, time.Minute)
+
+	assert.Equal(t, map[string]int{"a": 1, "c": 3}, cache.List())
+}
+
+func TestSetReplacesValueAndExpiry(t *testing.T) {
+	cache, clock := newCache(2)
+	cache.Set("a", 1, time.Minute)
+	cache.Set("a", 2, time.Hour)
+	clock.now = clock.now.Add(2 * time.Minute)
+
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestWithoutCapacity(t *testing.T) {
+	cache, _ := newCache(0)
+	for i, key := range []string{"a", "b", "c"} {
+		cache.Set(key, i, time.Minute)
+	}
+
+	assert.Equal(t, 3, cache.Len())
+}
+
+func TestDelete(t *testing.T) {
+	cache, _ := newCache(3)
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, time.Minute)
+	cache.Set("c", 3, time.Minute)
+
+	assert.True(t, cache.Delete("a"))
+	assert.False(t, cache.Delete("a"))
+
+	removed := cache.DeleteFunc(func(_ string, value int) bool { return value > 2 })
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, map[string]int{"b": 2}, cache.List())
+
+	assert.Equal(t, 1, cache.Purge())
+	assert.Zero(t, cache.Len())
+}
+
+func TestListSkipsExpiredEntries(t *testing.T) {
+	cache, clock := newCache(2)
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, time.Hour)
+	clock.now = clock.now.Add(2 * time.Minute)
+
+	assert.Equal(t, map[string]int{"b": 2}, cache.List())
+	// expired entries are counted until they are evicted
+	assert.Equal(t, 2, cache.Len())
+}
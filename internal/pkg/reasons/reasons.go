@@ -0,0 +1,23 @@
+// Package reasons holds the machine-readable reasons of failed requests, shared by the REST, gRPC and ext_authz APIs.
+package reasons
+
+const (
+	UserNotFound              = "user_not_found"
+	ContextNotFound           = "context_not_found"
+	ClientNotFound            = "client_not_found"
+	ScopeNotAllowed           = "scope_not_allowed"
+	Unauthenticated           = "unauthenticated"
+	ClientNotPrivileged       = "client_not_privileged"
+	ClientNotRegistered       = "client_not_registered"
+	InvalidTarget             = "invalid_target"
+	InvalidCheck              = "invalid_check"
+	InvalidUser               = "invalid_user"
+	CDSIDUnresolved           = "cdsid_unresolved"
+	TargetNotFound            = "target_not_found"
+	UpstreamNotFound          = "upstream_not_found"
+	UpstreamUnauthorized      = "upstream_unauthorized"
+	UpstreamUnavailable       = "upstream_unavailable"
+	UpstreamTimeout           = "upstream_timeout"
+	UpstreamMalformedResponse = "upstream_malformed_response"
+	Internal                  = "internal_error"
+)
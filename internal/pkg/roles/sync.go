@@ -0,0 +1,171 @@
+package roles
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/store"
+	yaml "sigs.k8s.io/yaml/goyaml.v3"
+)
+
+const yamlIndent = 2
+
+// Change is a single field of a role that differs between roles.yaml and PLUMS.
+type Change struct {
+	ID     string
+	Field  string
+	Local  string
+	Remote string
+}
+
+// Report is the drift between the roles in roles.yaml and the roles in PLUMS, compared by ID.
+type Report struct {
+	Added   []store.Role
+	Removed []store.Role
+	Changed []Change
+}
+
+// HasDrift reports whether roles.yaml differs from PLUMS.
+func (r Report) HasDrift() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0 || len(r.Changed) > 0
+}
+
+// Diff compares the local roles against the roles in PLUMS.
+func Diff(local []store.Role, remote []plums.Role) Report {
+	localByID := make(map[string]store.Role, len(local))
+	for _, role := range local {
+		localByID[role.ID] = role
+	}
+
+	remoteByID := make(map[string]plums.Role, len(remote))
+	for _, role := range remote {
+		remoteByID[role.ID] = role
+	}
+
+	var report Report
+	for _, role := range remote {
+		existing, ok := localByID[role.ID]
+		if !ok {
+			report.Added = append(report.Added, toStoreRole(role))
+			continue
+		}
+
+		if existing.Name != role.Name {
+			report.Changed = append(report.Changed, Change{ID: role.ID, Field: "name", Local: existing.Name, Remote: role.Name})
+		}
+
+		if existing.Description != role.Description {
+			report.Changed = append(report.Changed, Change{ID: role.ID, Field: "description", Local: existing.Description, Remote: role.Description})
+		}
+	}
+
+	for _, role := range local {
+		if _, ok := remoteByID[role.ID]; !ok {
+			report.Removed = append(report.Removed, role)
+		}
+	}
+
+	sort.Slice(report.Added, func(i, j int) bool { return report.Added[i].ID < report.Added[j].ID })
+
+	return report
+}
+
+// Apply returns the local roles brought in line with PLUMS, keeping the order of roles.yaml and appending new roles.
+func Apply(local []store.Role, report Report) []store.Role {
+	removed := make(map[string]struct{}, len(report.Removed))
+	for _, role := range report.Removed {
+		removed[role.ID] = struct{}{}
+	}
+
+	changes := make(map[string][]Change)
+	for _, change := range report.Changed {
+		changes[change.ID] = append(changes[change.ID], change)
+	}
+
+	result := make([]store.Role, 0, len(local)+len(report.Added))
+	for _, role := range local {
+		if _, ok := removed[role.ID]; ok {
+			continue
+		}
+
+		for _, change := range changes[role.ID] {
+			switch change.Field {
+			case "name":
+				role.Name = change.Remote
+			case "description":
+				role.Description = change.Remote
+			}
+		}
+
+		result = append(result, role)
+	}
+
+	return append(result, report.Added...)
+}
+
+// Read reads the roles from a roles.yaml file.
+func Read(filePath string) ([]store.Role, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read roles file [%s]: %w", filePath, err)
+	}
+
+	var definition roleDefinition
+	if err := yaml.Unmarshal(data, &definition); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal roles file [%s]: %w", filePath, err)
+	}
+
+	roles := make([]store.Role, len(definition.Roles))
+	for i, role := range definition.Roles {
+		roles[i] = store.Role(role)
+	}
+
+	return roles, nil
+}
+
+// Write replaces the roles in a roles.yaml file.
+func Write(filePath string, roles []store.Role) error {
+	definition := roleDefinition{Roles: make([]role, len(roles))}
+	for i, r := range roles {
+		definition.Roles[i] = role(r)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(yamlIndent)
+	if err := encoder.Encode(definition); err != nil {
+		return fmt.Errorf("failed to marshal roles: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to marshal roles: %w", err)
+	}
+
+	if err := os.WriteFile(filePath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write roles file [%s]: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// roleDefinition mirrors store.RoleDefinition with YAML tags, so that roles.yaml keeps its field order.
+type roleDefinition struct {
+	Roles []role `yaml:"roles"`
+}
+
+type role struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+}
+
+func toStoreRole(role plums.Role) store.Role {
+	return store.Role{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+	}
+}
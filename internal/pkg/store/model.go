@@ -48,6 +48,34 @@ type Client struct {
 	DependantScopes    []string `json:"dependant_scopes"`
 }
 
+type ScopeType string
+
+const (
+	// ScopeTypeFunctionality scopes grant permission groups.
+	ScopeTypeFunctionality ScopeType = "functionality"
+	// ScopeTypeData scopes grant access domains, the partners, distributors and markets whose data is visible.
+	ScopeTypeData ScopeType = "data"
+)
+
+func (t ScopeType) String() string {
+	return string(t)
+}
+
+type Domain string
+
+const (
+	// DomainPartner is the partner of the user context itself.
+	DomainPartner Domain = "partner"
+	// DomainDistributor is the distributor of the user context, i.e. all of its partners.
+	DomainDistributor Domain = "distributor"
+	// DomainMarket is the market of the user context.
+	DomainMarket Domain = "market"
+)
+
+func (d Domain) String() string {
+	return string(d)
+}
+
 type RoleDefinition struct {
 	Roles []Role `json:"roles"`
 }
@@ -66,6 +94,7 @@ type Scope struct {
 	Key              string            `json:"key"`
 	Label            string            `json:"label"`
 	Description      string            `json:"description"`
+	Type             ScopeType         `json:"type"`
 	PermissionGroups []PermissionGroup `json:"-"`
 }
 
@@ -91,6 +120,11 @@ type RoleMapping struct {
 type Mapping struct {
 	Filter           Filter   `json:"filter"`
 	PermissionGroups []string `json:"permission_groups"`
+	// Domains are granted by mappings of data scopes, the partner domain is granted when none is set.
+	Domains []Domain `json:"domains"`
+	// Inherit propagates the permission groups of the mapping from an NSC context down to the partners under its
+	// distributor, as inherited contexts.
+	Inherit bool `json:"inherit"`
 }
 
 type Filter struct {
@@ -116,3 +150,23 @@ func ToUserTypeString(userType []UserType) []string {
 
 	return arr
 }
+
+type RouteDefinition struct {
+	Routes []Route `json:"routes"`
+}
+
+// Route requires permission groups of a scope for the requests matching its host, path and methods, it is enforced
+// by the gateway through the ext_authz endpoint.
+type Route struct {
+	// Host matches the host of the request exactly, any host when empty.
+	Host string `json:"host"`
+	// Path is a pattern of slash separated segments, {name} and * match a single segment and a trailing ** the rest.
+	Path string `json:"path"`
+	// Methods match any method when empty.
+	Methods []string `json:"methods"`
+	// Scope is implied by the scope directory of the routes, routes of clients name it.
+	Scope            string   `json:"scope"`
+	PermissionGroups []string `json:"permission_groups"`
+	// Client limits the route to the requests of the client, it is set on the routes declared by a client.
+	Client string `json:"-"`
+}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Change summarizes the content a reload of the store added, removed or changed.
+type Change struct {
+	// Revision is the revision of the store after the reload.
+	Revision uint64
+	Digest   string
+	// Scopes are keyed by scope key and Roles by role ID.
+	Scopes Diff
+	Roles  Diff
+	// RoleMappings are keyed by scope key and role ID, e.g. user-admin/<role ID>.
+	RoleMappings Diff
+	// PermissionGroups are keyed by scope key and permission group key, e.g. user-admin/view_user_details.
+	PermissionGroups Diff
+}
+
+// Diff lists the keys of the added, removed and changed items, each sorted.
+type Diff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Reload loads the IAM configuration again and replaces the content of the store with it when it changed, the store
+// keeps its content when the configuration fails to load. The content is swapped at once, so that every read sees a
+// single revision. It must not be called concurrently.
+func (store *AccessControlStore) Reload() (Change, bool, error) {
+	next := NewAccessControlStore(store.rootDir)
+	if err := next.Process(); err != nil {
+		return Change{}, false, err
+	}
+
+	if next.Digest() == store.Digest() {
+		return Change{}, false, nil
+	}
+
+	change := Change{
+		Digest:           next.Digest(),
+		Scopes:           diff(scopesByKey(store), scopesByKey(next)),
+		Roles:            diff(rolesByID(store), rolesByID(next)),
+		RoleMappings:     diff(roleMappingsByKey(store), roleMappingsByKey(next)),
+		PermissionGroups: diff(permissionGroupsByKey(store), permissionGroupsByKey(next)),
+	}
+
+	store.mu.Lock()
+	store.Clients = next.Clients
+	store.Roles = next.Roles
+	store.Scopes = next.Scopes
+	store.RoleMappings = next.RoleMappings
+	store.Routes = next.Routes
+	store.digest.Store(next.digest.Load())
+	change.Revision = store.revision.Add(1)
+	store.mu.Unlock()
+
+	return change, true, nil
+}
+
+// Run reloads the store every interval until ctx is done and passes the changes to onChange, so that changes of the
+// IAM configuration are picked up without a restart.
+func (store *AccessControlStore) Run(ctx context.Context, interval time.Duration, onChange func(Change)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			change, changed, err := store.Reload()
+			if err != nil {
+				slog.Error("failed to reload access-control in-memory data", slog.Any("error", err))
+				continue
+			}
+
+			if changed {
+				slog.Info("access-control in-memory data reloaded", slog.Uint64("revision", change.Revision), slog.String("digest", change.Digest))
+				onChange(change)
+			}
+		}
+	}
+}
+
+func diff[V any](previous, next map[string]V) Diff {
+	var d Diff
+	for key, value := range next {
+		old, ok := previous[key]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, key)
+		case !reflect.DeepEqual(old, value):
+			d.Changed = append(d.Changed, key)
+		}
+	}
+
+	for key := range previous {
+		if _, ok := next[key]; !ok {
+			d.Removed = append(d.Removed, key)
+		}
+	}
+
+	slices.Sort(d.Added)
+	slices.Sort(d.Removed)
+	slices.Sort(d.Changed)
+
+	return d
+}
+
+// scopesByKey returns the scopes of the store without their permission groups, which are compared on their own.
+func scopesByKey(store *AccessControlStore) map[string]Scope {
+	scopes := make(map[string]Scope)
+	for _, scope := range store.Scopes.Values() {
+		scope.PermissionGroups = nil
+		scopes[scope.Key] = scope
+	}
+
+	return scopes
+}
+
+func rolesByID(store *AccessControlStore) map[string]Role {
+	roles := make(map[string]Role)
+	for _, role := range store.Roles.Values() {
+		roles[role.ID] = role
+	}
+
+	return roles
+}
+
+func roleMappingsByKey(store *AccessControlStore) map[string][]Mapping {
+	mappings := make(map[string][]Mapping)
+	for key, mapping := range store.RoleMappings.List() {
+		scope, role, _ := strings.Cut(strings.TrimPrefix(key, "scope:"), "/role:")
+		mappings[scope+"/"+role] = mapping
+	}
+
+	return mappings
+}
+
+func permissionGroupsByKey(store *AccessControlStore) map[string]PermissionGroup {
+	groups := make(map[string]PermissionGroup)
+	for _, scope := range store.Scopes.Values() {
+		for _, group := range scope.PermissionGroups {
+			groups[scope.Key+"/"+group.Key] = group
+		}
+	}
+
+	return groups
+}
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testdataDir = "../../tests/integration-tests/testdata/iam"
+	roleID      = "35d1e3d7-c453-4a15-a1e1-8fd021e46434"
+)
+
+// copyTestdata copies the IAM configuration of the integration tests, so that a test can change it.
+func copyTestdata(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	err := filepath.WalkDir(testdataDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(testdataDir, path)
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return os.MkdirAll(filepath.Join(dir, rel), 0o755)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(filepath.Join(dir, rel), data, 0o600)
+	})
+	require.NoError(t, err)
+
+	return dir
+}
+
+// replaceInFile replaces old with replacement in the file of the directory.
+func replaceInFile(t *testing.T, dir, file, old, replacement string) {
+	t.Helper()
+
+	path := filepath.Join(dir, file)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), old)
+
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), old, replacement, 1)), 0o600))
+}
+
+func TestReload(t *testing.T) {
+	tests := map[string]struct {
+		change func(t *testing.T, dir string)
+		want   Change
+	}{
+		"removed scope": {
+			change: func(t *testing.T, dir string) {
+				require.NoError(t, os.RemoveAll(filepath.Join(dir, "scopes", "retail-data")))
+			},
+			want: Change{
+				Scopes:       Diff{Removed: []string{"retail-data"}},
+				RoleMappings: Diff{Removed: []string{"retail-data/" + roleID}},
+			},
+		},
+		"changed scope": {
+			change: func(t *testing.T, dir string) {
+				replaceInFile(t, dir, "scopes/retail-data/scope.yaml", "label: Retail Data", "label: Retail")
+			},
+			want: Change{Scopes: Diff{Changed: []string{"retail-data"}}},
+		},
+		"changed permission group": {
+			change: func(t *testing.T, dir string) {
+				replaceInFile(t, dir, "scopes/user-admin/permission-groups.yaml", "label: Assign admin rights", "label: Assign admins")
+			},
+			want: Change{PermissionGroups: Diff{Changed: []string{"user-admin/assign_admin_rights"}}},
+		},
+		"changed role mapping": {
+			change: func(t *testing.T, dir string) {
+				replaceInFile(t, dir, "scopes/retail-data/role-mapping/user-administrator.yaml", "- PARMA", "- DEALER")
+			},
+			want: Change{RoleMappings: Diff{Changed: []string{"retail-data/" + roleID}}},
+		},
+		"added role": {
+			change: func(t *testing.T, dir string) {
+				replaceInFile(t, dir, "config/roles.yaml", "roles:\n", "roles:\n  - id: viewer\n    name: Viewer\n")
+			},
+			want: Change{Roles: Diff{Added: []string{"viewer"}}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := copyTestdata(t)
+			store := NewAccessControlStore(dir)
+			require.NoError(t, store.Process())
+			revision, digest := store.Revision(), store.Digest()
+
+			tt.change(t, dir)
+			change, changed, err := store.Reload()
+			require.NoError(t, err)
+			require.True(t, changed)
+
+			assert.Equal(t, revision+1, change.Revision)
+			assert.Equal(t, revision+1, store.Revision())
+			assert.NotEqual(t, digest, change.Digest)
+			assert.Equal(t, store.Digest(), change.Digest)
+
+			change.Revision, change.Digest = 0, ""
+			assert.Equal(t, tt.want, change)
+		})
+	}
+}
+
+func TestReloadWithoutChange(t *testing.T) {
+	store := NewAccessControlStore(copyTestdata(t))
+	require.NoError(t, store.Process())
+	revision := store.Revision()
+
+	change, changed, err := store.Reload()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, Change{}, change)
+	assert.Equal(t, revision, store.Revision())
+}
+
+func TestReloadKeepsContentOnError(t *testing.T) {
+	dir := copyTestdata(t)
+	store := NewAccessControlStore(dir)
+	require.NoError(t, store.Process())
+	revision, digest := store.Revision(), store.Digest()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "roles.yaml"), []byte("roles: ["), 0o600))
+	_, changed, err := store.Reload()
+	assert.Error(t, err)
+	assert.False(t, changed)
+
+	assert.Equal(t, revision, store.Revision())
+	assert.Equal(t, digest, store.Digest())
+	_, err = store.GetRole(roleID)
+	assert.NoError(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	previous := map[string]int{"a": 1, "b": 2, "c": 3}
+	next := map[string]int{"b": 2, "c": 4, "e": 5, "d": 4}
+
+	assert.Equal(t, Diff{Added: []string{"d", "e"}, Removed: []string{"a"}, Changed: []string{"c"}}, diff(previous, next))
+	assert.Equal(t, Diff{}, diff(previous, previous))
+}
@@ -1,12 +1,16 @@
 package store
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/volvo-cars/connect-access-control/internal/pkg/utils"
 )
@@ -21,11 +25,21 @@ var (
 )
 
 type AccessControlStore struct {
-	rootDir      string
+	rootDir string
+	// mu guards the content against a reload, which swaps all of it at once.
+	mu           sync.RWMutex
 	Clients      *KV[string, Client]
 	Scopes       *KV[string, Scope]
 	Roles        *KV[string, Role]
 	RoleMappings *KV[string, []Mapping]
+	// Routes are keyed by the client or scope that declared them.
+	Routes *KV[string, []Route]
+
+	// revision is incremented by every successful Process, so that derived state can be rebuilt after a reload.
+	revision atomic.Uint64
+	// digest is the hash of the loaded content, unlike the revision it is the same for the same content across
+	// processes and restarts.
+	digest atomic.Pointer[string]
 }
 
 func NewAccessControlStore(rootDir string) *AccessControlStore {
@@ -35,11 +49,15 @@ func NewAccessControlStore(rootDir string) *AccessControlStore {
 		Scopes:       NewKV[string, Scope](),
 		Roles:        NewKV[string, Role](),
 		RoleMappings: NewKV[string, []Mapping](),
+		Routes:       NewKV[string, []Route](),
 	}
 }
 
 // GetClient retrieves a client from the in-memory database by its key.
 func (store *AccessControlStore) GetClient(clientID string) (Client, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
 	key := clientKey(clientID)
 	client, exists := store.Clients.Get(key)
 	if !exists {
@@ -51,11 +69,17 @@ func (store *AccessControlStore) GetClient(clientID string) (Client, error) {
 
 // GetClients retrieves all clients from the in-memory database.
 func (store *AccessControlStore) GetClients() ([]Client, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
 	return store.Clients.Values(), nil
 }
 
 // GetScope retrieves a scope from the in-memory database by its key.
 func (store *AccessControlStore) GetScope(scopeID string) (Scope, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
 	key := ScopeKey(scopeID)
 	scope, exists := store.Scopes.Get(key)
 	if !exists {
@@ -67,11 +91,17 @@ func (store *AccessControlStore) GetScope(scopeID string) (Scope, error) {
 
 // GetScopes retrieves all scopes from the in-memory database.
 func (store *AccessControlStore) GetScopes() ([]Scope, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
 	return store.Scopes.Values(), nil
 }
 
 // GetRole retrieves a role from the in-memory database by its key.
 func (store *AccessControlStore) GetRole(roleID string) (Role, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
 	key := roleKey(roleID)
 	role, exists := store.Roles.Get(key)
 	if !exists {
@@ -83,11 +113,17 @@ func (store *AccessControlStore) GetRole(roleID string) (Role, error) {
 
 // GetRoles retrieves all roles from the in-memory database.
 func (store *AccessControlStore) GetRoles() ([]Role, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
 	return store.Roles.Values(), nil
 }
 
 // GetRoleMapping retrieves a role mapping from the in-memory database by its scope and role keys.
 func (store *AccessControlStore) GetRoleMapping(scopeID, roleID string) (RoleMapping, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
 	key := roleMappingKey(scopeID, roleID)
 	mapping, exists := store.RoleMappings.Get(key)
 	if !exists {
@@ -102,6 +138,9 @@ func (store *AccessControlStore) GetRoleMapping(scopeID, roleID string) (RoleMap
 
 // GetRoleMappings retrieves all role mappings from the in-memory database by its scope key.
 func (store *AccessControlStore) GetRoleMappings(scopeID string) ([]RoleMapping, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
 	arr := make([]RoleMapping, 0)
 
 	store.RoleMappings.Filter(func(key string, value []Mapping) bool {
@@ -132,9 +171,62 @@ func (store *AccessControlStore) Process() error {
 		return fmt.Errorf("failed to load scopes error: %w", err)
 	}
 
+	digest, err := store.computeDigest()
+	if err != nil {
+		return fmt.Errorf("failed to compute digest error: %w", err)
+	}
+	store.digest.Store(&digest)
+
+	store.revision.Add(1)
 	return nil
 }
 
+// GetRoutes retrieves the routes of all clients and scopes from the in-memory database.
+func (store *AccessControlStore) GetRoutes() ([]Route, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	var routes []Route
+	for _, declared := range store.Routes.Values() {
+		routes = append(routes, declared...)
+	}
+
+	return routes, nil
+}
+
+// Revision returns the number of times the store was processed.
+func (store *AccessControlStore) Revision() uint64 {
+	return store.revision.Load()
+}
+
+// Digest returns the hex encoded SHA-256 hash of the loaded content, it is empty before the store was processed.
+func (store *AccessControlStore) Digest() string {
+	if digest := store.digest.Load(); digest != nil {
+		return *digest
+	}
+
+	return ""
+}
+
+// computeDigest hashes the content of the store, maps are encoded in the order of their keys.
+func (store *AccessControlStore) computeDigest() (string, error) {
+	// permission groups are not part of the JSON encoding of scopes
+	permissionGroups := make(map[string][]PermissionGroup)
+	for key, scope := range store.Scopes.List() {
+		permissionGroups[key] = scope.PermissionGroups
+	}
+
+	hash := sha256.New()
+	encoder := json.NewEncoder(hash)
+	for _, content := range []any{store.Clients.List(), store.Scopes.List(), permissionGroups, store.Roles.List(), store.RoleMappings.List(), store.Routes.List()} {
+		if err := encoder.Encode(content); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 func (store *AccessControlStore) processClients() error {
 	clientsDir := path.Join(store.rootDir, "clients")
 	dirs, err := utils.ReadDirNames(clientsDir)
@@ -155,6 +247,19 @@ func (store *AccessControlStore) processClients() error {
 
 		client := definition.Client
 		store.Clients.Set(clientKey(client.ID), client)
+
+		routes, err := populateRoutes(dir)
+		if err != nil {
+			return err
+		}
+
+		for i := range routes {
+			if routes[i].Scope == "" {
+				return fmt.Errorf("route [%s] of client [%s] has no scope", routes[i].Path, client.ID)
+			}
+			routes[i].Client = client.ID
+		}
+		store.Routes.Set(clientKey(client.ID), routes)
 	}
 
 	return nil
@@ -221,6 +326,16 @@ func (store *AccessControlStore) populateScopes(dirPath string) error {
 		return fmt.Errorf("failed to load role mapping error: %w", err)
 	}
 
+	routes, err := populateRoutes(dirPath)
+	if err != nil {
+		return err
+	}
+
+	for i := range routes {
+		routes[i].Scope = scope.Key
+	}
+	store.Routes.Set(ScopeKey(scope.Key), routes)
+
 	for _, roleMapping := range roleMappings {
 		key := roleMappingKey(scope.Key, roleMapping.RoleID)
 		if _, exists := store.RoleMappings.Get(key); exists {
@@ -245,6 +360,8 @@ func (store *AccessControlStore) populateScope(dirPath string) (Scope, error) {
 	permGroupFile := path.Join(dirPath, "permission-groups.yaml")
 	permissionGroupsDefinition, err := utils.YAMLUnmarshal[PermissionGroupDefinition](permGroupFile)
 	if errors.Is(err, utils.ErrNotFound) {
+		// data scopes grant access domains and may have no permission groups
+		store.Scopes.Set(ScopeKey(scope.Key), scope)
 		return scope, nil
 	}
 	if err != nil {
@@ -286,6 +403,20 @@ func (store *AccessControlStore) populateRoleMappings(dirPath string) ([]RoleMap
 	return roleMappings, nil
 }
 
+// populateRoutes reads the optional routes file of a client or scope directory.
+func populateRoutes(dirPath string) ([]Route, error) {
+	routesFile := path.Join(dirPath, "routes.yaml")
+	definition, err := utils.YAMLUnmarshal[RouteDefinition](routesFile)
+	if errors.Is(err, utils.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal routes file [%s]: %w", routesFile, err)
+	}
+
+	return definition.Routes, nil
+}
+
 func (store *AccessControlStore) scanScopesDir() ([]string, error) {
 	scopesDirPath := path.Join(store.rootDir, "scopes")
 	dirs, err := utils.ReadDirNames(scopesDirPath)
@@ -0,0 +1,42 @@
+package token
+
+import (
+	"errors"
+	"time"
+
+	env "github.com/caarlos0/env/v11"
+)
+
+type Config struct {
+	// Enabled serves POST /v1/iam/token and the JWKS of the signing keys, it requires authentication to be enabled.
+	Enabled bool          `env:"TOKEN_ENABLED" envDefault:"false"`
+	Issuer  string        `env:"TOKEN_ISSUER"`
+	TTL     time.Duration `env:"TOKEN_TTL" envDefault:"5m"`
+
+	// KeyFiles are PEM encoded RSA or EC private keys, the first one signs and all of them are published. A key is
+	// rotated by appending the new key, moving it to the front once consumers refreshed their key set and removing the
+	// old key once its tokens expired. The files are read again every KeyRefreshInterval.
+	KeyFiles           []string      `env:"TOKEN_KEY_FILES"`
+	KeyRefreshInterval time.Duration `env:"TOKEN_KEY_REFRESH_INTERVAL" envDefault:"1m"`
+}
+
+func LoadConfig() (*Config, error) {
+	cfg := &Config{}
+	if err := env.Parse(cfg); err != nil {
+		return nil, err
+	}
+
+	if !cfg.Enabled {
+		return cfg, nil
+	}
+
+	if cfg.Issuer == "" {
+		return nil, errors.New("TOKEN_ISSUER is required when tokens are enabled")
+	}
+
+	if len(cfg.KeyFiles) == 0 {
+		return nil, errors.New("TOKEN_KEY_FILES is required when tokens are enabled")
+	}
+
+	return cfg, nil
+}
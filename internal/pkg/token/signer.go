@@ -0,0 +1,211 @@
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/auth"
+)
+
+var ErrInvalidKey = errors.New("invalid signing key")
+
+// Token is a signed token and the time it expires at.
+type Token struct {
+	Value     string
+	ExpiresAt time.Time
+}
+
+type signingKey struct {
+	method jwt.SigningMethod
+	key    crypto.Signer
+	jwk    auth.JWK
+}
+
+// Signer mints the tokens of the service with the configured key files.
+type Signer struct {
+	cfg *Config
+
+	mu   sync.RWMutex
+	keys []signingKey
+}
+
+func NewSigner(cfg *Config) (*Signer, error) {
+	s := &Signer{cfg: cfg}
+	if err := s.Load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Sign mints a token for the subject and audience, carrying the registered claims of the signer and the claims.
+func (s *Signer) Sign(subject, audience string, claims map[string]any) (Token, error) {
+	s.mu.RLock()
+	active := s.keys[0]
+	s.mu.RUnlock()
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Token{}, fmt.Errorf("failed to generate token ID: %w", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.TTL)
+
+	mapClaims := jwt.MapClaims{}
+	for key, value := range claims {
+		mapClaims[key] = value
+	}
+	mapClaims["iss"] = s.cfg.Issuer
+	mapClaims["sub"] = subject
+	mapClaims["aud"] = audience
+	mapClaims["iat"] = now.Unix()
+	mapClaims["nbf"] = now.Unix()
+	mapClaims["exp"] = expiresAt.Unix()
+	mapClaims["jti"] = hex.EncodeToString(id)
+
+	token := jwt.NewWithClaims(active.method, mapClaims)
+	token.Header["kid"] = active.jwk.Kid
+
+	signed, err := token.SignedString(active.key)
+	if err != nil {
+		return Token{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return Token{Value: signed, ExpiresAt: expiresAt}, nil
+}
+
+// JWKS returns the public keys of all key files, consumers verify the tokens of the signer with them.
+func (s *Signer) JWKS() auth.JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	jwks := auth.JWKS{Keys: make([]auth.JWK, len(s.keys))}
+	for i, key := range s.keys {
+		jwks.Keys[i] = key.jwk
+	}
+
+	return jwks
+}
+
+// Load reads the key files again, the keys are kept unchanged when any of them cannot be read.
+func (s *Signer) Load() error {
+	keys := make([]signingKey, 0, len(s.cfg.KeyFiles))
+	for _, file := range s.cfg.KeyFiles {
+		key, err := readKey(file)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: no key files", ErrInvalidKey)
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Run periodically reads the key files until ctx is done, so that rotated keys are picked up without a restart.
+func (s *Signer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.KeyRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Load(); err != nil {
+				slog.Error("failed to reload token signing keys", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+func readKey(file string) (signingKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return signingKey{}, fmt.Errorf("failed to read key file [%s]: %w", file, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return signingKey{}, fmt.Errorf("%w: key file [%s] is not PEM encoded", ErrInvalidKey, file)
+	}
+
+	key, err := parsePrivateKey(block)
+	if err != nil {
+		return signingKey{}, fmt.Errorf("%w: key file [%s]: %w", ErrInvalidKey, file, err)
+	}
+
+	var method jwt.SigningMethod
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			method = jwt.SigningMethodES256
+		case elliptic.P384():
+			method = jwt.SigningMethodES384
+		case elliptic.P521():
+			method = jwt.SigningMethodES512
+		default:
+			return signingKey{}, fmt.Errorf("%w: key file [%s] has an unsupported curve", ErrInvalidKey, file)
+		}
+	default:
+		return signingKey{}, fmt.Errorf("%w: key file [%s] holds a %T", ErrInvalidKey, file, key)
+	}
+
+	jwk, err := auth.NewJWK("", method.Alg(), key.Public())
+	if err != nil {
+		return signingKey{}, err
+	}
+
+	if jwk.Kid, err = jwk.Thumbprint(); err != nil {
+		return signingKey{}, err
+	}
+
+	return signingKey{method: method, key: key, jwk: jwk}, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type %T", key)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block [%s]", block.Type)
+	}
+}
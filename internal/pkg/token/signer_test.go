@@ -0,0 +1,150 @@
+package token
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeKey writes the PEM block of a private key to a file of the directory.
+func writeKey(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+
+	file := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+
+	return file
+}
+
+func TestReadKey(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	p224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	require.NoError(t, err)
+
+	ecDER, err := x509.MarshalECPrivateKey(p256)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(p384)
+	require.NoError(t, err)
+	p224DER, err := x509.MarshalECPrivateKey(p224)
+	require.NoError(t, err)
+
+	notPEM := filepath.Join(dir, "key.txt")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a key"), 0o600))
+
+	tests := map[string]struct {
+		file string
+		alg  string
+		err  error
+	}{
+		"PKCS#1 RSA key":    {file: writeKey(t, dir, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), alg: "RS256"},
+		"SEC 1 P-256 key":   {file: writeKey(t, dir, "p256.pem", "EC PRIVATE KEY", ecDER), alg: "ES256"},
+		"PKCS#8 P-384 key":  {file: writeKey(t, dir, "p384.pem", "PRIVATE KEY", pkcs8), alg: "ES384"},
+		"unsupported curve": {file: writeKey(t, dir, "p224.pem", "EC PRIVATE KEY", p224DER), err: ErrInvalidKey},
+		"unsupported block": {file: writeKey(t, dir, "cert.pem", "CERTIFICATE", []byte("certificate")), err: ErrInvalidKey},
+		"invalid key":       {file: writeKey(t, dir, "invalid.pem", "RSA PRIVATE KEY", []byte("key")), err: ErrInvalidKey},
+		"not PEM encoded":   {file: notPEM, err: ErrInvalidKey},
+		"missing key file":  {file: filepath.Join(dir, "missing.pem"), err: os.ErrNotExist},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, err := readKey(tt.file)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.alg, key.method.Alg())
+			assert.Equal(t, tt.alg, key.jwk.Alg)
+
+			// the key ID is the thumbprint of the public key
+			thumbprint, err := key.jwk.Thumbprint()
+			require.NoError(t, err)
+			assert.Equal(t, thumbprint, key.jwk.Kid)
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	dir := t.TempDir()
+	active, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	next, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	activeDER, err := x509.MarshalECPrivateKey(active)
+	require.NoError(t, err)
+
+	signer, err := NewSigner(&Config{
+		Issuer: "https://iam.example.com",
+		TTL:    5 * time.Minute,
+		KeyFiles: []string{
+			writeKey(t, dir, "active.pem", "EC PRIVATE KEY", activeDER),
+			writeKey(t, dir, "next.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(next)),
+		},
+	})
+	require.NoError(t, err)
+
+	// all keys are published, the first one signs
+	jwks := signer.JWKS()
+	require.Len(t, jwks.Keys, 2)
+
+	token, err := signer.Sign("jsmith", "client-a", map[string]any{"roles": []string{"user-admin"}, "sub": "ignored"})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), token.ExpiresAt, time.Minute)
+
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(token.Value, claims, func(token *jwt.Token) (any, error) {
+		assert.Equal(t, jwks.Keys[0].Kid, token.Header["kid"])
+		return jwks.Keys[0].PublicKey()
+	}, jwt.WithIssuer("https://iam.example.com"), jwt.WithAudience("client-a"), jwt.WithValidMethods([]string{"ES256"}))
+	require.NoError(t, err)
+	assert.True(t, parsed.Valid)
+
+	// the registered claims of the signer take precedence over the claims
+	assert.Equal(t, "jsmith", claims["sub"])
+	assert.Equal(t, []any{"user-admin"}, claims["roles"])
+	assert.NotEmpty(t, claims["jti"])
+}
+
+func TestLoadKeepsKeysOnError(t *testing.T) {
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	file := writeKey(t, dir, "key.pem", "EC PRIVATE KEY", der)
+	cfg := &Config{KeyFiles: []string{file}}
+	signer, err := NewSigner(cfg)
+	require.NoError(t, err)
+	jwks := signer.JWKS()
+
+	cfg.KeyFiles = append(cfg.KeyFiles, filepath.Join(dir, "missing.pem"))
+	assert.ErrorIs(t, signer.Load(), os.ErrNotExist)
+	assert.Equal(t, jwks, signer.JWKS())
+
+	cfg.KeyFiles = nil
+	assert.ErrorIs(t, signer.Load(), ErrInvalidKey)
+	assert.Equal(t, jwks, signer.JWKS())
+}
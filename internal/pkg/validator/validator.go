@@ -21,6 +21,11 @@ const (
 	clientsDir                 = "clients"
 	schemaDir                  = "config/schema"
 	roleMappingDir             = "role-mapping"
+	configDir                  = "config"
+	identityProvidersFile      = "identity-providers.yaml"
+	identityProvidersSchema    = "identity-providers.yaml"
+	routesFile                 = "routes.yaml"
+	routesSchemaFile           = "routes.yaml"
 )
 
 type SchemaValidator struct {
@@ -43,6 +48,8 @@ func (v *SchemaValidator) LoadSchema() error {
 		scopeSchemaFile,
 		roleMappingSchemaFile,
 		permissionGroupsSchemaFile,
+		identityProvidersSchema,
+		routesSchemaFile,
 	}
 
 	for _, fileName := range schemaFiles {
@@ -64,6 +71,7 @@ func (v *SchemaValidator) Validate() ([]*ValidationResult, error) {
 	validators := []func() ([]*ValidationResult, error){
 		v.validateClients,
 		v.validateScopes,
+		v.validateConfig,
 	}
 
 	results := make([]*ValidationResult, 0)
@@ -98,11 +106,35 @@ func (v *SchemaValidator) validateClients() ([]*ValidationResult, error) {
 		if result != nil {
 			results = append(results, result)
 		}
+
+		result, err = v.validateRoutes(dir)
+		if err != nil && !errors.Is(err, utils.ErrNotFound) {
+			return nil, err
+		}
+
+		if result != nil {
+			results = append(results, result)
+		}
 	}
 
 	return results, nil
 }
 
+func (v *SchemaValidator) validateConfig() ([]*ValidationResult, error) {
+	schemaPath := path.Join(v.RootDir, v.SchemaDir, identityProvidersSchema)
+	documentPath := path.Join(v.RootDir, configDir, identityProvidersFile)
+
+	result, err := v.loader.Validate(schemaPath, documentPath)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return []*ValidationResult{result}, nil
+}
+
 func (v *SchemaValidator) validateScopes() ([]*ValidationResult, error) {
 	dirs, err := v.scanScopesSubDirNames()
 	if err != nil {
@@ -150,6 +182,7 @@ func (v *SchemaValidator) validateScopeDir(dirPath string) ([]*ValidationResult,
 	validators := []func(string) (*ValidationResult, error){
 		v.validateScope,
 		v.validatePermissionGroups,
+		v.validateRoutes,
 	}
 
 	results := make([]*ValidationResult, 0)
@@ -210,6 +243,12 @@ func (v *SchemaValidator) validatePermissionGroups(dirPath string) (*ValidationR
 	return v.loader.Validate(schemaPath, documentPath)
 }
 
+func (v *SchemaValidator) validateRoutes(dirPath string) (*ValidationResult, error) {
+	schemaPath := path.Join(v.RootDir, v.SchemaDir, routesSchemaFile)
+	documentPath := path.Join(dirPath, routesFile)
+	return v.loader.Validate(schemaPath, documentPath)
+}
+
 func (v *SchemaValidator) validateClient(dirPath string) (*ValidationResult, error) {
 	schemaPath := path.Join(v.RootDir, v.SchemaDir, clientSchemaFile)
 	documentPath := path.Join(dirPath, clientFile)
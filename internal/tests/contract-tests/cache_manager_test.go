@@ -0,0 +1,47 @@
+package contract_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cachemanager "github.com/volvo-cars/connect-access-control/internal/pkg/gateway/cache-manager"
+)
+
+// distributorEnv names an NSC with PARMA retailers in the cache-manager the CACHE_* variables point at.
+const distributorEnv = "CONTRACT_DISTRIBUTOR_ID"
+
+type nopCollector struct{}
+
+func (nopCollector) ObserveRequestTimeWithOp(string, string, string, string, int, time.Duration) {}
+
+// TestPartnersByDistributor pins the distributorId filter of cache-manager that inherited contexts rely on, the fake
+// upstreams implement the same filter. It runs against a real cache-manager only, when distributorEnv is set.
+func TestPartnersByDistributor(t *testing.T) {
+	distributorID := os.Getenv(distributorEnv)
+	if distributorID == "" {
+		t.Skipf("%s is not set", distributorEnv)
+	}
+
+	cfg, err := cachemanager.LoadConfig()
+	require.NoError(t, err)
+
+	gateway := cachemanager.New(cfg, nopCollector{})
+	ctx := context.Background()
+
+	partners, err := gateway.GetPartnersByDistributor(ctx, distributorID, cachemanager.PartnerTypeParma.String())
+	require.NoError(t, err)
+	require.NotEmpty(t, partners, "distributor [%s] has no PARMA partners", distributorID)
+
+	for _, partner := range partners {
+		require.Equal(t, distributorID, partner.DistributorID, "partner [%s]", partner.ID)
+		require.NotEmpty(t, partner.ParmaPartnerCode, "partner [%s]", partner.ID)
+	}
+
+	// an unknown distributor matches no partner, rather than the filter being ignored
+	partners, err = gateway.GetPartnersByDistributor(ctx, "contract-test-unknown-distributor", cachemanager.PartnerTypeParma.String())
+	require.NoError(t, err)
+	require.Empty(t, partners)
+}
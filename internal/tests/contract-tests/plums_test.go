@@ -0,0 +1,42 @@
+package contract_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/gateway/plums"
+)
+
+// userEnv names a user with an email and identities in the PLUMS the PLUMS_* variables point at.
+const userEnv = "CONTRACT_USER_CDSID"
+
+// TestUserSearch pins the PLUMS user search by email and by provider identity that the email and identity lookups
+// rely on, the fake upstreams implement the same search. It runs against a real PLUMS only, when userEnv is set.
+func TestUserSearch(t *testing.T) {
+	cdsid := os.Getenv(userEnv)
+	if cdsid == "" {
+		t.Skipf("%s is not set", userEnv)
+	}
+
+	cfg, err := plums.LoadConfig()
+	require.NoError(t, err)
+
+	gateway := plums.New(cfg, nopCollector{})
+	ctx := context.Background()
+
+	user, err := gateway.GetUser(ctx, plums.ByCDSID(cdsid))
+	require.NoError(t, err)
+	require.NotEmpty(t, user.Email, "user [%s] has no email", cdsid)
+
+	found, err := gateway.GetUser(ctx, plums.ByEmail(user.Email))
+	require.NoError(t, err)
+	require.Equal(t, user.UserID, found.UserID)
+
+	for _, identity := range user.UserIdentities {
+		found, err := gateway.GetUser(ctx, plums.ByIdentity(identity.Provider, identity.ProviderUserID))
+		require.NoError(t, err, "identity [%s/%s]", identity.Provider, identity.ProviderUserID)
+		require.Equal(t, user.UserID, found.UserID, "identity [%s/%s]", identity.Provider, identity.ProviderUserID)
+	}
+}
@@ -0,0 +1,20 @@
+package integration_test
+
+import (
+	"net/http"
+
+	integration "github.com/volvo-cars/connect-access-control/internal/tests/util"
+)
+
+func (suite *IntegrationSuite) TestPurgePartnersIsServedByTheAdminServer() {
+	admin := integration.NewRequester(testAdminPort)
+
+	var response any
+	res, err := admin.DoRequest("admin/cache/partners", http.MethodDelete, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+
+	res, err = suite.requester.DoRequest("admin/cache/partners", http.MethodDelete, nil, nil, nil)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+}
@@ -0,0 +1,66 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+)
+
+func (suite *IntegrationSuite) TestUsersAccessBatch() {
+	body, err := json.Marshal(v1.UserAccessBatchRequest{
+		CDSIDs: []string{"jsmith", "jdoe", "nobody"},
+		Scopes: []string{"user-admin"},
+	})
+	suite.Require().NoError(err)
+
+	res, err := http.Post(suite.requester.CreateEndpointURL("v1/iam/users/access:batch"), "application/json", bytes.NewReader(body))
+	suite.Require().NoError(err)
+	defer res.Body.Close()
+
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+	suite.Equal("application/x-ndjson", res.Header.Get("Content-Type"))
+
+	results := make(map[string]v1.UserAccessBatchResult)
+	decoder := json.NewDecoder(res.Body)
+	for decoder.More() {
+		var result v1.UserAccessBatchResult
+		suite.Require().NoError(decoder.Decode(&result))
+		results[result.CDSID] = result
+	}
+
+	suite.Require().Len(results, 3)
+
+	suite.Nil(results["jsmith"].Error)
+	suite.NotEmpty(results["jsmith"].Accesses)
+
+	suite.Nil(results["jdoe"].Error)
+	suite.NotEmpty(results["jdoe"].Accesses)
+
+	suite.Require().NotNil(results["nobody"].Error)
+	suite.Equal(http.StatusNotFound, results["nobody"].Error.Code)
+	suite.Equal(v1.ReasonUserNotFound, results["nobody"].Error.Reason)
+	suite.Empty(results["nobody"].Accesses)
+}
+
+func (suite *IntegrationSuite) TestUsersAccessBatchInvalid() {
+	for name, request := range map[string]v1.UserAccessBatchRequest{
+		"without users":  {Scopes: []string{"user-admin"}},
+		"empty user":     {CDSIDs: []string{"jsmith", ""}, Scopes: []string{"user-admin"}},
+		"without scopes": {CDSIDs: []string{"jsmith"}},
+	} {
+		res, err := suite.requester.DoRequest("v1/iam/users/access:batch", http.MethodPost, request, nil, nil)
+		suite.Require().NoError(err)
+		suite.Equal(http.StatusBadRequest, res.StatusCode, name)
+	}
+}
+
+func (suite *IntegrationSuite) TestUsersAccessBatchTooLarge() {
+	// far more than the body size allowed for BATCH_MAX_USERS users
+	request := v1.UserAccessBatchRequest{CDSIDs: []string{strings.Repeat("x", 4<<20)}, Scopes: []string{"user-admin"}}
+	res, err := suite.requester.DoRequest("v1/iam/users/access:batch", http.MethodPost, request, nil, nil)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusRequestEntityTooLarge, res.StatusCode)
+}
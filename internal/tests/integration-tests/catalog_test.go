@@ -0,0 +1,108 @@
+package integration_test
+
+import (
+	"net/http"
+
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+)
+
+func (suite *IntegrationSuite) TestGetScopesSortedAndPaged() {
+	tests := map[string]struct {
+		query string
+		keys  []string
+		page  v1.Page
+	}{
+		"default order": {
+			query: "",
+			keys:  []string{"retail-admin", "retail-data", "user-admin"},
+			page:  v1.Page{Total: 3},
+		},
+		"descending": {
+			query: "?sort=-key",
+			keys:  []string{"user-admin", "retail-data", "retail-admin"},
+			page:  v1.Page{Total: 3},
+		},
+		"page": {
+			query: "?limit=1&offset=1",
+			keys:  []string{"retail-data"},
+			page:  v1.Page{Total: 3, Offset: 1, Limit: 1},
+		},
+		"offset past the end": {
+			query: "?offset=5",
+			keys:  []string{},
+			page:  v1.Page{Total: 3, Offset: 5},
+		},
+		"type": {
+			query: "?type=functionality&sort=-key",
+			keys:  []string{"user-admin", "retail-admin"},
+			page:  v1.Page{Total: 2},
+		},
+	}
+
+	for name, tt := range tests {
+		suite.Run(name, func() {
+			var response v1.ScopesResponse
+			res, err := suite.requester.DoRequest("v1/iam/scopes"+tt.query, http.MethodGet, nil, &response, nil)
+			suite.Require().NoError(err)
+			suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+			keys := make([]string, len(response.Data))
+			for i, scope := range response.Data {
+				keys[i] = scope.Key
+			}
+			suite.Equal(tt.keys, keys)
+
+			suite.Require().NotNil(response.Meta)
+			suite.Equal(&tt.page, response.Meta.Page)
+		})
+	}
+}
+
+func (suite *IntegrationSuite) TestGetListsInvalidQuery() {
+	for _, path := range []string{
+		"v1/iam/scopes?limit=0",
+		"v1/iam/scopes?limit=1001",
+		"v1/iam/roles?offset=-1",
+		"v1/iam/clients?sort=description",
+	} {
+		res, err := suite.requester.DoRequest(path, http.MethodGet, nil, nil, nil)
+		suite.Require().NoError(err)
+		suite.Equal(http.StatusBadRequest, res.StatusCode, path)
+	}
+}
+
+func (suite *IntegrationSuite) TestGetClientsByDependantScope() {
+	var response v1.ClientsResponse
+	res, err := suite.requester.DoRequest("v1/iam/clients?dependant_scope=user-admin", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Require().Len(response.Data, 1)
+	suite.Equal("user-portal", response.Data[0].ID)
+	suite.Equal(1, response.Meta.Page.Total)
+}
+
+func (suite *IntegrationSuite) TestGetRolesByName() {
+	for name, total := range map[string]int{"administrator": 1, "ADMIN": 1, "dealer": 0} {
+		var response v1.RolesResponse
+		res, err := suite.requester.DoRequest("v1/iam/roles?name="+name, http.MethodGet, nil, &response, nil)
+		suite.Require().NoError(err)
+		suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+		suite.Len(response.Data, total, name)
+		suite.Equal(total, response.Meta.Page.Total, name)
+	}
+}
+
+func (suite *IntegrationSuite) TestGetRoleMappingsByPartnerType() {
+	var response v1.RoleMappingsResponse
+	res, err := suite.requester.DoRequest("v1/iam/scopes/user-admin/mappings?partner_type=NSC", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Require().Len(response.Data, 1)
+	suite.Equal([]v1.Mapping{
+		{PermissionGroups: []string{"view_user_details"}},
+		{Filter: v1.Filter{PartnerType: []string{"NSC"}}, PermissionGroups: []string{"assign_admin_rights"}},
+	}, response.Data[0].Mapping)
+}
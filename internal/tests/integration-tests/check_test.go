@@ -0,0 +1,111 @@
+package integration_test
+
+import (
+	"net/http"
+
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+)
+
+func (suite *IntegrationSuite) TestCheck() {
+	tests := map[string]struct {
+		path    string
+		allowed bool
+		reason  authz.CheckReason
+		context string
+	}{
+		"own partner": {
+			path:    "v1/iam/users/jsmith/check?scope=user-admin&permission_group=manage_user_details&partner=10001",
+			allowed: true,
+			reason:  authz.ReasonPartner,
+			context: "d6a1c2b3-0000-4000-8000-000000010001",
+		},
+		"partner under the distributor of an NSC context": {
+			path:    "v1/iam/users/jdoe/check?scope=user-admin&permission_group=assign_admin_rights&partner=10002",
+			allowed: true,
+			reason:  authz.ReasonDistributor,
+			context: "nsc-se",
+		},
+		"market of an NSC context": {
+			path:    "v1/iam/users/jdoe/check?scope=user-admin&permission_group=assign_admin_rights&market=SE",
+			allowed: true,
+			reason:  authz.ReasonMarket,
+			context: "nsc-se",
+		},
+		"partner outside of the contexts": {
+			path:   "v1/iam/users/jsmith/check?scope=user-admin&permission_group=manage_user_details&partner=10002",
+			reason: authz.ReasonNotCovered,
+		},
+		"permission group not granted": {
+			path:   "v1/iam/users/jsmith/check?scope=user-admin&permission_group=assign_admin_rights&partner=10001",
+			reason: authz.ReasonNotGranted,
+		},
+		"data scope distributor": {
+			path:    "v1/iam/users/check?email=jdoe@volvocars.biz&scope=retail-data&distributor=nsc-se",
+			allowed: true,
+			reason:  authz.ReasonDistributor,
+			context: "nsc-se",
+		},
+	}
+
+	for name, tt := range tests {
+		suite.Run(name, func() {
+			var response v1.DecisionResponse
+			res, err := suite.requester.DoRequest(tt.path, http.MethodGet, nil, &response, nil)
+			suite.Require().NoError(err)
+			suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+			suite.Equal(tt.allowed, response.Data.Allowed)
+			suite.Equal(tt.reason.String(), response.Data.Reason)
+			if tt.context == "" {
+				suite.Nil(response.Data.Context)
+				return
+			}
+
+			suite.Require().NotNil(response.Data.Context)
+			suite.Equal(tt.context, response.Data.Context.ID)
+		})
+	}
+}
+
+func (suite *IntegrationSuite) TestCheckTargetNotFound() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe/check?scope=user-admin&partner=77777", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(v1.ReasonTargetNotFound, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestCheckInvalidTarget() {
+	for _, path := range []string{
+		"v1/iam/users/jdoe/check?scope=user-admin",
+		"v1/iam/users/jdoe/check?scope=user-admin&partner=10001&market=SE",
+		"v1/iam/users/jdoe/check?partner=10001",
+	} {
+		res, err := suite.requester.DoRequest(path, http.MethodGet, nil, nil, nil)
+		suite.Require().NoError(err)
+		suite.Equal(http.StatusBadRequest, res.StatusCode, path)
+	}
+}
+
+func (suite *IntegrationSuite) TestCheckPermissionGroupOfDataScope() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe/check?scope=retail-data&permission_group=manage_user_details&distributor=nsc-se", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusBadRequest, res.StatusCode)
+	suite.Equal(v1.ReasonInvalidCheck, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestCheckInheritedContext() {
+	var response v1.DecisionResponse
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe/check?scope=retail-admin&partner=10002", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.True(response.Data.Allowed)
+	suite.Equal(authz.ReasonPartner.String(), response.Data.Reason)
+	suite.Require().NotNil(response.Data.Context)
+	suite.True(response.Data.Context.Inherited)
+}
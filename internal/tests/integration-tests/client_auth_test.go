@@ -0,0 +1,79 @@
+package integration_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+)
+
+func (suite *IntegrationSuite) TestClientScopeNotAllowed() {
+	var response v1.ErrorResponse
+	token := suite.token("jdoe", jwt.MapClaims{"azp": "dealer-app"})
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe/access?scope=user-admin", http.MethodGet, nil, &response, bearer(token))
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusForbidden, res.StatusCode)
+	suite.Equal(v1.ReasonScopeNotAllowed, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestClientNotRegistered() {
+	var response v1.ErrorResponse
+	token := suite.token("jdoe", jwt.MapClaims{"azp": "unknown-app"})
+	res, err := suite.requester.DoRequest("v1/iam/me/access?scope=user-admin", http.MethodGet, nil, &response, bearer(token))
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusForbidden, res.StatusCode)
+	suite.Equal(v1.ReasonClientNotRegistered, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestClientCertificate() {
+	headers := map[string]string{
+		clientCertHeader: `Hash=0a1b2c;Subject="CN=user-portal,OU=Connect,O=Volvo Cars"`,
+	}
+
+	var access v1.Response[[]v1.UserAccess]
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe/access?scope=user-admin", http.MethodGet, nil, &access, headers)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+
+	var response v1.ErrorResponse
+	res, err = suite.requester.DoRequest("v1/iam/users/jdoe/check?scope=retail-data&permission_group=view&market=SE", http.MethodGet, nil, &response, headers)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusForbidden, res.StatusCode)
+	suite.Equal(v1.ReasonScopeNotAllowed, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestClientCertificateHeaderOfUntrustedPeer() {
+	headers := map[string]string{
+		clientCertHeader: `Hash=0a1b2c;Subject="CN=user-portal,OU=Connect,O=Volvo Cars"`,
+		"X-Real-IP":      "127.0.0.1",
+	}
+
+	// from a trusted proxy the certificate of user-portal is not allowed the retail-data scope
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe/access?scope=retail-data", http.MethodGet, nil, nil, headers)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusForbidden, res.StatusCode)
+
+	// any other peer is served anonymously, the header is ignored
+	dialer := &net.Dialer{LocalAddr: &net.TCPAddr{IP: net.ParseIP(untrustedAddr)}}
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp4", net.JoinHostPort("127.0.0.1", testPort))
+		},
+	}}
+
+	req, err := http.NewRequest(http.MethodGet, suite.requester.CreateEndpointURL("v1/iam/users/jdoe/access?scope=retail-data"), nil)
+	suite.Require().NoError(err)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+
+	suite.Equal(http.StatusOK, resp.StatusCode)
+}
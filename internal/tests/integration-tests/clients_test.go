@@ -0,0 +1,46 @@
+package integration_test
+
+import (
+	"net/http"
+
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+)
+
+func (suite *IntegrationSuite) TestGetClientUserAccess() {
+	var response v1.ClientUserAccessResponse
+	res, err := suite.requester.DoRequest("v1/iam/clients/user-portal/users/jdoe/access", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Equal("user-portal", response.Data.Client.ID)
+	suite.Equal([]string{"user-admin"}, response.Data.Client.DependantScopes)
+	suite.Len(response.Data.Accesses, 2)
+}
+
+func (suite *IntegrationSuite) TestGetClientUserAccessWithoutDependantScopes() {
+	var response v1.ClientUserAccessResponse
+	res, err := suite.requester.DoRequest("v1/iam/clients/dealer-app/users/jdoe/access", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Equal("dealer-app", response.Data.Client.ID)
+	suite.Empty(response.Data.Accesses)
+}
+
+func (suite *IntegrationSuite) TestGetClientUserAccessScopeNotAllowed() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/clients/dealer-app/users/jdoe/access?scope=user-admin", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusForbidden, res.StatusCode)
+	suite.Equal(v1.ReasonScopeNotAllowed, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestGetClientUserAccessClientNotFound() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/clients/unknown/users/jdoe/access", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(v1.ReasonClientNotFound, response.Error.Reason)
+}
@@ -0,0 +1,46 @@
+package integration_test
+
+import (
+	"net/http"
+)
+
+func (suite *IntegrationSuite) TestCORSPreflight() {
+	for origin, allowed := range map[string]bool{
+		"https://dealer.volvocars.biz":      true,
+		"https://portal.eu.volvocars.biz":   true,
+		"https://volvocars.biz":             false,
+		"http://dealer.volvocars.biz":       false,
+		"https://dealer.volvocars.biz.evil": false,
+		"https://example.com":               false,
+	} {
+		res, err := suite.requester.DoRequest("v1/iam/me/access", http.MethodOptions, nil, nil, map[string]string{
+			"Origin":                         origin,
+			"Access-Control-Request-Method":  http.MethodGet,
+			"Access-Control-Request-Headers": "authorization",
+		})
+		suite.Require().NoError(err)
+
+		if !allowed {
+			suite.Equal(http.StatusForbidden, res.StatusCode, origin)
+			suite.Empty(res.Header.Get("Access-Control-Allow-Origin"), origin)
+			continue
+		}
+
+		suite.Equal(http.StatusNoContent, res.StatusCode, origin)
+		suite.Equal(origin, res.Header.Get("Access-Control-Allow-Origin"), origin)
+		suite.Contains(res.Header.Get("Access-Control-Allow-Headers"), "Authorization", origin)
+	}
+}
+
+func (suite *IntegrationSuite) TestCORSRequest() {
+	res, err := suite.requester.DoRequest("v1/iam/clients", http.MethodGet, nil, nil, map[string]string{"Origin": "https://dealer.volvocars.biz"})
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal("https://dealer.volvocars.biz", res.Header.Get("Access-Control-Allow-Origin"))
+	suite.Empty(res.Header.Get("Access-Control-Allow-Credentials"))
+
+	res, err = suite.requester.DoRequest("v1/iam/clients", http.MethodGet, nil, nil, map[string]string{"Origin": "https://example.com"})
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Empty(res.Header.Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,97 @@
+package integration_test
+
+import (
+	"net/http"
+)
+
+func (suite *IntegrationSuite) TestConditionalCatalog() {
+	for _, path := range []string{"v1/iam/scopes", "v1/iam/scopes/user-admin", "v1/iam/roles", "v1/iam/clients/user-portal"} {
+		res, err := suite.requester.DoRequest(path, http.MethodGet, nil, nil, nil)
+		suite.Require().NoError(err)
+		suite.Require().Equal(http.StatusOK, res.StatusCode, path)
+
+		tag := res.Header.Get("ETag")
+		suite.Require().NotEmpty(tag, path)
+		suite.Equal("no-cache", res.Header.Get("Cache-Control"), path)
+
+		res, err = suite.requester.DoRequest(path, http.MethodGet, nil, nil, map[string]string{"If-None-Match": tag})
+		suite.Require().NoError(err)
+		suite.Equal(http.StatusNotModified, res.StatusCode, path)
+		suite.Equal(tag, res.Header.Get("ETag"), path)
+
+		res, err = suite.requester.DoRequest(path, http.MethodGet, nil, nil, map[string]string{"If-None-Match": `"outdated", W/` + tag})
+		suite.Require().NoError(err)
+		suite.Equal(http.StatusNotModified, res.StatusCode, path)
+
+		res, err = suite.requester.DoRequest(path, http.MethodGet, nil, nil, map[string]string{"If-None-Match": `"outdated"`})
+		suite.Require().NoError(err)
+		suite.Equal(http.StatusOK, res.StatusCode, path)
+	}
+}
+
+func (suite *IntegrationSuite) TestConditionalUserAccess() {
+	path := "v1/iam/users/jsmith/access?scope=user-admin"
+
+	res, err := suite.requester.DoRequest(path, http.MethodGet, nil, nil, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	tag := res.Header.Get("ETag")
+	suite.Require().NotEmpty(tag)
+	suite.Equal("private, no-cache", res.Header.Get("Cache-Control"))
+
+	res, err = suite.requester.DoRequest(path, http.MethodGet, nil, nil, map[string]string{"If-None-Match": tag})
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusNotModified, res.StatusCode)
+
+	// the access of another user has another tag
+	res, err = suite.requester.DoRequest("v1/iam/users/jdoe/access?scope=user-admin", http.MethodGet, nil, nil, map[string]string{"If-None-Match": tag})
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.NotEqual(tag, res.Header.Get("ETag"))
+}
+
+func (suite *IntegrationSuite) TestNoStoreByDefault() {
+	res, err := suite.requester.DoRequest("v1/iam/users/jsmith", http.MethodGet, nil, nil, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Equal("no-store", res.Header.Get("Cache-Control"))
+	suite.Empty(res.Header.Get("ETag"))
+}
+
+func (suite *IntegrationSuite) TestConditionalCatalogFailure() {
+	tests := map[string]struct {
+		path   string
+		status int
+	}{
+		"unknown scope":   {path: "v1/iam/scopes/unknown", status: http.StatusNotFound},
+		"unknown client":  {path: "v1/iam/clients/unknown", status: http.StatusNotFound},
+		"invalid sorting": {path: "v1/iam/roles?sort=unknown", status: http.StatusBadRequest},
+	}
+
+	for name, tt := range tests {
+		suite.Run(name, func() {
+			res, err := suite.requester.DoRequest(tt.path, http.MethodGet, nil, nil, map[string]string{"If-None-Match": "*"})
+			suite.Require().NoError(err)
+
+			suite.Equal(tt.status, res.StatusCode)
+			suite.Empty(res.Header.Get("ETag"))
+		})
+	}
+}
+
+func (suite *IntegrationSuite) TestConditionalCatalogQuery() {
+	tag := func(path string) string {
+		res, err := suite.requester.DoRequest(path, http.MethodGet, nil, nil, nil)
+		suite.Require().NoError(err)
+		suite.Require().Equal(http.StatusOK, res.StatusCode, path)
+
+		return res.Header.Get("ETag")
+	}
+
+	// every page and filter of a catalog has its own tag, the order of the query does not matter
+	suite.NotEqual(tag("v1/iam/roles?limit=1"), tag("v1/iam/roles?limit=1&offset=1"))
+	suite.NotEqual(tag("v1/iam/scopes"), tag("v1/iam/scopes?type=data"))
+	suite.Equal(tag("v1/iam/roles?limit=1&sort=-id"), tag("v1/iam/roles?sort=-id&limit=1"))
+}
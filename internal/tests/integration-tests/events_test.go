@@ -0,0 +1,117 @@
+package integration_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+)
+
+const eventTimeout = 2 * time.Second
+
+type event struct {
+	id     string
+	typ    string
+	change v1.ConfigChange
+}
+
+// subscribe opens the event stream and passes its events to the returned channel until the test ends.
+func (suite *IntegrationSuite) subscribe(lastEventID string) <-chan event {
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.T().Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, suite.requester.CreateEndpointURL("v1/iam/events"), nil)
+	suite.Require().NoError(err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+	suite.Require().Equal("text/event-stream", res.Header.Get("Content-Type"))
+
+	events := make(chan event, 8)
+	go func() {
+		defer res.Body.Close()
+		defer close(events)
+
+		var e event
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			field, value, _ := strings.Cut(scanner.Text(), ": ")
+			switch field {
+			case "id":
+				e.id = value
+			case "event":
+				e.typ = value
+			case "data":
+				_ = json.Unmarshal([]byte(value), &e.change)
+			case "":
+				if e.typ != "" {
+					events <- e
+				}
+				e = event{}
+			}
+		}
+	}()
+
+	return events
+}
+
+func (suite *IntegrationSuite) nextEvent(events <-chan event) event {
+	select {
+	case e, ok := <-events:
+		suite.Require().True(ok, "event stream closed")
+		return e
+	case <-time.After(eventTimeout):
+		suite.FailNow("no event received")
+		return event{}
+	}
+}
+
+func (suite *IntegrationSuite) TestEvents() {
+	events := suite.subscribe("")
+
+	// the scope is moved into place at once, so that no reload sees it half written
+	staged := filepath.Join(suite.T().TempDir(), "events-test")
+	suite.Require().NoError(os.MkdirAll(filepath.Join(staged, "role-mapping"), 0o755))
+	suite.Require().NoError(os.WriteFile(filepath.Join(staged, "scope.yaml"), []byte("scope:\n  key: events-test\n  label: Events Test\n  type: functionality\n"), 0o644))
+	suite.Require().NoError(os.WriteFile(filepath.Join(staged, "permission-groups.yaml"), []byte("permission_groups:\n  - key: view_events\n    label: View events\n"), 0o644))
+
+	scopeDir := filepath.Join(suite.iamRootDir, "scopes", "events-test")
+	suite.Require().NoError(os.Rename(staged, scopeDir))
+
+	added := suite.nextEvent(events)
+	suite.Equal("config.revision", added.typ)
+	suite.NotEmpty(added.change.Digest)
+	suite.Equal([]string{"events-test"}, added.change.Scopes.Added)
+	suite.Equal([]string{"events-test/view_events"}, added.change.PermissionGroups.Added)
+	suite.Empty(added.change.Roles)
+
+	suite.Require().NoError(os.Rename(scopeDir, staged))
+
+	removed := suite.nextEvent(events)
+	suite.Equal("config.revision", removed.typ)
+	suite.Equal(added.change.Revision+1, removed.change.Revision)
+	suite.Equal([]string{"events-test"}, removed.change.Scopes.Removed)
+	suite.Equal([]string{"events-test/view_events"}, removed.change.PermissionGroups.Removed)
+
+	suite.Run("resume after the last event", func() {
+		replayed := suite.nextEvent(suite.subscribe(added.id))
+		suite.Equal(removed, replayed)
+	})
+
+	suite.Run("resume with an unknown event", func() {
+		reset := suite.nextEvent(suite.subscribe("unknown-1"))
+		suite.Equal("config.reset", reset.typ)
+		suite.Equal(removed.change.Revision, reset.change.Revision)
+		suite.Equal(removed.change.Digest, reset.change.Digest)
+	})
+}
@@ -0,0 +1,120 @@
+package integration_test
+
+import (
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/volvo-cars/connect-access-control/internal/api/extauthz"
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+)
+
+func (suite *IntegrationSuite) TestExtAuthz() {
+	tests := map[string]struct {
+		method   string
+		path     string
+		subject  string
+		client   string
+		status   int
+		reason   string
+		contexts string
+	}{
+		"permission group granted in a partner context": {
+			method:   http.MethodGet,
+			path:     "ext-authz/api/users/42",
+			subject:  "jsmith",
+			status:   http.StatusOK,
+			contexts: "d6a1c2b3-0000-4000-8000-000000010001",
+		},
+		"permission group not granted": {
+			method:  http.MethodPut,
+			path:    "ext-authz/api/users/42/admin-rights/partner",
+			subject: "jsmith",
+			status:  http.StatusForbidden,
+			reason:  extauthz.ReasonNotGranted,
+		},
+		"permission group granted in an NSC context": {
+			method:   http.MethodPut,
+			path:     "ext-authz/api/users/42/admin-rights/partner",
+			subject:  "jdoe",
+			status:   http.StatusOK,
+			contexts: "nsc-se",
+		},
+		"method not declared": {
+			method:  http.MethodDelete,
+			path:    "ext-authz/api/users/42",
+			subject: "jsmith",
+			status:  http.StatusForbidden,
+			reason:  extauthz.ReasonRouteNotDeclared,
+		},
+		"route without permission groups": {
+			method:  http.MethodGet,
+			path:    "ext-authz/api/health",
+			subject: "jsmith",
+			status:  http.StatusOK,
+		},
+		"route without permission groups for a client without a user": {
+			method: http.MethodGet,
+			path:   "ext-authz/api/health",
+			client: "dealer-app",
+			status: http.StatusOK,
+		},
+		"dot-dot segment": {
+			method:  http.MethodGet,
+			path:    "ext-authz/api/health/../users/42",
+			subject: "jsmith",
+			status:  http.StatusBadRequest,
+			reason:  extauthz.ReasonInvalidPath,
+		},
+		"encoded slash": {
+			method:  http.MethodGet,
+			path:    "ext-authz/api/users%2F42",
+			subject: "jsmith",
+			status:  http.StatusBadRequest,
+			reason:  extauthz.ReasonInvalidPath,
+		},
+		"route of the client": {
+			method:  http.MethodGet,
+			path:    "ext-authz/api/users/42",
+			subject: "jsmith",
+			client:  "dealer-app",
+			status:  http.StatusForbidden,
+			reason:  extauthz.ReasonNotGranted,
+		},
+	}
+
+	for name, tt := range tests {
+		suite.Run(name, func() {
+			claims := jwt.MapClaims{}
+			if tt.client != "" {
+				claims["azp"] = tt.client
+			}
+
+			var response v1.ErrorResponse
+			var target any
+			if tt.status != http.StatusOK {
+				target = &response
+			}
+
+			res, err := suite.requester.DoRequest(tt.path, tt.method, nil, target, bearer(suite.token(tt.subject, claims)))
+			suite.Require().NoError(err)
+			suite.Require().Equal(tt.status, res.StatusCode)
+
+			if tt.status != http.StatusOK {
+				suite.Equal(tt.reason, response.Error.Reason)
+				return
+			}
+
+			suite.Equal(tt.subject, res.Header.Get(extauthz.SubjectHeader))
+			suite.Equal(tt.contexts, res.Header.Get(extauthz.ContextsHeader))
+		})
+	}
+}
+
+func (suite *IntegrationSuite) TestExtAuthzUnauthenticated() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("ext-authz/api/health", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusUnauthorized, res.StatusCode)
+	suite.Equal(v1.ReasonUnauthenticated, response.Error.Reason)
+}
@@ -0,0 +1,137 @@
+package integration_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	pb "github.com/volvo-cars/connect-access-control/internal/api/rpc/accesscontrolv1"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/authz"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcClient connects to the gRPC server of the suite, the connection is closed with the test.
+func (suite *IntegrationSuite) grpcClient() (pb.AccessControlServiceClient, *grpc.ClientConn) {
+	conn, err := grpc.NewClient("localhost:"+testGRPCPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewAccessControlServiceClient(conn), conn
+}
+
+func grpcContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 5*time.Second)
+}
+
+func (suite *IntegrationSuite) TestGRPCHealth() {
+	_, conn := suite.grpcClient()
+	ctx, cancel := grpcContext()
+	defer cancel()
+
+	res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: pb.AccessControlService_ServiceDesc.ServiceName}, grpc.WaitForReady(true))
+	suite.Require().NoError(err)
+	suite.Equal(healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+}
+
+func (suite *IntegrationSuite) TestGRPCGetUserAccess() {
+	client, _ := suite.grpcClient()
+	ctx, cancel := grpcContext()
+	defer cancel()
+
+	res, err := client.GetUserAccess(ctx, &pb.GetUserAccessRequest{
+		User:   &pb.UserLookup{Kind: &pb.UserLookup_Cdsid{Cdsid: "jsmith"}},
+		Scopes: []string{"user-admin"},
+	}, grpc.WaitForReady(true))
+	suite.Require().NoError(err)
+	suite.Require().Len(res.GetAccesses(), 1)
+	suite.Equal("d6a1c2b3-0000-4000-8000-000000010001", res.GetAccesses()[0].GetContext().GetId())
+	suite.Contains(res.GetAccesses()[0].GetPermissionGroups()["user-admin"].GetKeys(), "manage_user_details")
+
+	_, err = client.GetUserAccess(ctx, &pb.GetUserAccessRequest{
+		User:   &pb.UserLookup{Kind: &pb.UserLookup_Cdsid{Cdsid: "unknown"}},
+		Scopes: []string{"user-admin"},
+	})
+	suite.Equal(codes.NotFound, status.Code(err))
+	_, err = client.GetUserAccess(ctx, &pb.GetUserAccessRequest{
+		User:   &pb.UserLookup{Kind: &pb.UserLookup_UserId{UserId: "../../roles"}},
+		Scopes: []string{"user-admin"},
+	})
+	suite.Equal(codes.InvalidArgument, status.Code(err))
+
+	_, err = client.GetUserAccess(ctx, &pb.GetUserAccessRequest{
+		User: &pb.UserLookup{Kind: &pb.UserLookup_Cdsid{Cdsid: "jsmith"}},
+	})
+	suite.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (suite *IntegrationSuite) TestGRPCBatchCheck() {
+	client, _ := suite.grpcClient()
+	ctx, cancel := grpcContext()
+	defer cancel()
+
+	user := &pb.UserLookup{Kind: &pb.UserLookup_Cdsid{Cdsid: "jsmith"}}
+	res, err := client.BatchCheck(ctx, &pb.BatchCheckRequest{Checks: []*pb.CheckRequest{
+		{User: user, Scope: "user-admin", PermissionGroup: "manage_user_details", Target: &pb.Target{Kind: &pb.Target_Partner{Partner: "10001"}}},
+		{User: user, Scope: "user-admin", PermissionGroup: "manage_user_details", Target: &pb.Target{Kind: &pb.Target_Partner{Partner: "10002"}}},
+		{User: user, Scope: "user-admin", PermissionGroup: "manage_user_details"},
+		{User: &pb.UserLookup{Kind: &pb.UserLookup_Cdsid{Cdsid: "unknown"}}, Scope: "user-admin", Target: &pb.Target{Kind: &pb.Target_Market{Market: "SE"}}},
+	}}, grpc.WaitForReady(true))
+	suite.Require().NoError(err)
+	suite.Require().Len(res.GetResults(), 4)
+
+	suite.True(res.GetResults()[0].GetDecision().GetAllowed())
+	suite.Equal(authz.ReasonPartner.String(), res.GetResults()[0].GetDecision().GetReason())
+	suite.False(res.GetResults()[1].GetDecision().GetAllowed())
+	suite.Equal(authz.ReasonNotCovered.String(), res.GetResults()[1].GetDecision().GetReason())
+	suite.Equal(int32(codes.InvalidArgument), res.GetResults()[2].GetError().GetCode())
+	suite.Equal(int32(codes.NotFound), res.GetResults()[3].GetError().GetCode())
+
+	// a batch holds at most BATCH_MAX_CHECKS checks
+	checks := make([]*pb.CheckRequest, batchMaxChecks+1)
+	for i := range checks {
+		checks[i] = &pb.CheckRequest{User: user, Scope: "user-admin", Target: &pb.Target{Kind: &pb.Target_Market{Market: "SE"}}}
+	}
+	_, err = client.BatchCheck(ctx, &pb.BatchCheckRequest{Checks: checks})
+	suite.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (suite *IntegrationSuite) TestGRPCCheckScopeNotAllowed() {
+	client, _ := suite.grpcClient()
+	ctx, cancel := grpcContext()
+	defer cancel()
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+suite.token("jdoe", jwt.MapClaims{"azp": "dealer-app"}))
+	_, err := client.Check(ctx, &pb.CheckRequest{
+		User:            &pb.UserLookup{Kind: &pb.UserLookup_Cdsid{Cdsid: "jdoe"}},
+		Scope:           "user-admin",
+		PermissionGroup: "assign_admin_rights",
+		Target:          &pb.Target{Kind: &pb.Target_Market{Market: "SE"}},
+	}, grpc.WaitForReady(true))
+	suite.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+func (suite *IntegrationSuite) TestGRPCCatalog() {
+	client, _ := suite.grpcClient()
+	ctx, cancel := grpcContext()
+	defer cancel()
+
+	scopes, err := client.ListScopes(ctx, &pb.ListScopesRequest{}, grpc.WaitForReady(true))
+	suite.Require().NoError(err)
+	suite.NotEmpty(scopes.GetScopes())
+
+	clientRes, err := client.GetClient(ctx, &pb.GetClientRequest{Id: "user-portal"})
+	suite.Require().NoError(err)
+	suite.Equal([]string{"user-admin"}, clientRes.GetDependantScopes())
+
+	mappings, err := client.ListRoleMappings(ctx, &pb.ListRoleMappingsRequest{Scope: "user-admin"})
+	suite.Require().NoError(err)
+	suite.NotEmpty(mappings.GetMappings())
+
+	_, err = client.GetRole(ctx, &pb.GetRoleRequest{Id: "unknown"})
+	suite.Equal(codes.NotFound, status.Code(err))
+}
@@ -1,35 +1,165 @@
 package integration_test
 
 import (
-	"log"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/fs"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 	"github.com/volvo-cars/connect-access-control/internal/app/authz"
 	"github.com/volvo-cars/connect-access-control/internal/config"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/auth"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/fakeupstreams"
+	integration "github.com/volvo-cars/connect-access-control/internal/tests/util"
+)
+
+const (
+	testPort      = "18080"
+	testAdminPort = "18081"
+	testGRPCPort  = "19090"
+	iamRootDir    = "testdata/iam"
+	fixturesFile  = "testdata/fixtures.yaml"
+
+	reloadInterval = 50 * time.Millisecond
+	batchMaxChecks = 10
+
+	tokenIssuer   = "https://issuer.test"
+	tokenAudience = "connect-access-control"
+	tokenKeyID    = "test-key"
+
+	clientCertHeader = "X-Forwarded-Client-Cert"
+	// trustedProxies are the loopback addresses the requests of the suite come from, see untrustedAddr
+	trustedProxies = "127.0.0.1,::1"
+	untrustedAddr  = "127.0.0.2"
+
+	// mintedIssuer is the issuer of the tokens minted by the service
+	mintedIssuer = "https://access-control.test"
 )
 
 type IntegrationSuite struct {
 	suite.Suite
+
+	upstreams *httptest.Server
+	requester integration.Requester
+	// signingKey signs the bearer tokens of the suite, its public key is served from a JWKS file
+	signingKey *rsa.PrivateKey
+	// iamRootDir is a copy of the IAM configuration of testdata, which tests may change
+	iamRootDir string
 }
 
 func (suite *IntegrationSuite) SetupSuite() {
+	// Serve PLUMS and cache-manager from fixtures, so that the suite runs offline
+	fixtures, err := fakeupstreams.LoadFixtures(fixturesFile)
+	suite.Require().NoError(err)
+
+	suite.upstreams = fakeupstreams.NewTestServer(fixtures)
+	for key, value := range fakeupstreams.Env(suite.upstreams.URL) {
+		suite.T().Setenv(key, value)
+	}
+
+	// Serve a copy of the IAM configuration, reloaded quickly so that tests can change it
+	suite.iamRootDir = filepath.Join(suite.T().TempDir(), "iam")
+	suite.Require().NoError(copyDir(iamRootDir, suite.iamRootDir))
+	suite.T().Setenv("IAM_ROOT_DIR", suite.iamRootDir)
+	suite.T().Setenv("IAM_RELOAD_INTERVAL", reloadInterval.String())
+	suite.T().Setenv("HTTP_PORT", testPort)
+	suite.T().Setenv("HTTP_ADMIN_PORT", testAdminPort)
+	suite.T().Setenv("GRPC_PORT", testGRPCPort)
+	suite.T().Setenv("BATCH_MAX_CHECKS", strconv.Itoa(batchMaxChecks))
+
+	// Authenticate bearer tokens signed by the suite, requests without a token stay anonymous
+	suite.signingKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+
+	jwk, err := auth.NewJWK(tokenKeyID, "RS256", &suite.signingKey.PublicKey)
+	suite.Require().NoError(err)
+
+	jwks, err := json.Marshal(auth.JWKS{Keys: []auth.JWK{jwk}})
+	suite.Require().NoError(err)
+
+	jwksFile := filepath.Join(suite.T().TempDir(), "jwks.json")
+	suite.Require().NoError(os.WriteFile(jwksFile, jwks, 0o600))
+
+	suite.T().Setenv("AUTH_ENABLED", "true")
+	suite.T().Setenv("AUTH_REQUIRED", "false")
+	suite.T().Setenv("AUTH_ISSUER", tokenIssuer)
+	suite.T().Setenv("AUTH_AUDIENCE", tokenAudience)
+	suite.T().Setenv("AUTH_JWKS_FILE", jwksFile)
+	suite.T().Setenv("AUTH_CLIENT_CERT_HEADER", clientCertHeader)
+	suite.T().Setenv("AUTH_CLIENT_CERT_TRUSTED_PROXIES", trustedProxies)
+
+	// Mint tokens with an EC key of the suite
+	mintingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(mintingKey)
+	suite.Require().NoError(err)
+
+	keyFile := filepath.Join(suite.T().TempDir(), "token-key.pem")
+	suite.Require().NoError(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	suite.T().Setenv("TOKEN_ENABLED", "true")
+	suite.T().Setenv("TOKEN_ISSUER", mintedIssuer)
+	suite.T().Setenv("TOKEN_KEY_FILES", keyFile)
+
+	suite.T().Setenv("EXTAUTHZ_ENABLED", "true")
+
 	// Load the configuration
 	cfg, err := config.New()
-	if err != nil {
-		log.Printf("failed to load configuration: %v", err)
-		os.Exit(1)
-	}
+	suite.Require().NoError(err)
 
 	// Run authorization setup with the loaded configuration
-
 	go func() {
 		authz.Run(cfg)
 	}()
+
+	suite.requester = integration.NewRequester(testPort)
+	_, err = suite.requester.RetryPing(suite.requester.CreateEndpointURL("v1/iam/scopes"))
+	suite.Require().NoError(err)
+}
+
+func (suite *IntegrationSuite) TearDownSuite() {
+	suite.upstreams.Close()
 }
 
 func TestApp(t *testing.T) {
 	testSuite := new(IntegrationSuite)
 	suite.Run(t, testSuite)
 }
+
+// copyDir copies the files of the src directory tree to dst.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(target, data, 0o644)
+	})
+}
@@ -0,0 +1,89 @@
+package integration_test
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+)
+
+// token signs a token of the suite issuer for the subject, claims override the defaults.
+func (suite *IntegrationSuite) token(subject string, claims jwt.MapClaims) string {
+	now := time.Now()
+	mapClaims := jwt.MapClaims{
+		"iss": tokenIssuer,
+		"aud": tokenAudience,
+		"sub": subject,
+		"azp": "user-portal",
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	for key, value := range claims {
+		mapClaims[key] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, mapClaims)
+	token.Header["kid"] = tokenKeyID
+
+	signed, err := token.SignedString(suite.signingKey)
+	suite.Require().NoError(err)
+
+	return signed
+}
+
+func bearer(token string) map[string]string {
+	return map[string]string{"Authorization": "Bearer " + token}
+}
+
+func (suite *IntegrationSuite) TestGetMe() {
+	var response v1.UserResponse
+	res, err := suite.requester.DoRequest("v1/iam/me", http.MethodGet, nil, &response, bearer(suite.token("jdoe", nil)))
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Equal("jdoe", response.Data.CDSID)
+}
+
+func (suite *IntegrationSuite) TestGetMeAccess() {
+	var response v1.Response[[]v1.UserAccess]
+	res, err := suite.requester.DoRequest("v1/iam/me/access?scope=user-admin&primary=true", http.MethodGet, nil, &response, bearer(suite.token("jdoe", nil)))
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Require().Len(response.Data, 1)
+	suite.Equal("10001", response.Data[0].Context.Tag)
+}
+
+func (suite *IntegrationSuite) TestGetMeUnauthenticated() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/me", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusUnauthorized, res.StatusCode)
+	suite.Equal(v1.ReasonUnauthenticated, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestInvalidToken() {
+	for name, token := range map[string]string{
+		"expired":        suite.token("jdoe", jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}),
+		"other issuer":   suite.token("jdoe", jwt.MapClaims{"iss": "https://other.test"}),
+		"other audience": suite.token("jdoe", jwt.MapClaims{"aud": "other"}),
+		"no audience":    suite.token("jdoe", jwt.MapClaims{"aud": nil}),
+		"malformed":      "not-a-token",
+	} {
+		res, err := suite.requester.DoRequest("v1/iam/me", http.MethodGet, nil, nil, bearer(token))
+		suite.Require().NoError(err)
+		suite.Equal(http.StatusUnauthorized, res.StatusCode, name)
+	}
+
+	// the rejected requests are counted as denials of anonymous callers
+	res, err := http.Get("http://localhost:" + testAdminPort + "/metrics")
+	suite.Require().NoError(err)
+	defer res.Body.Close()
+
+	metrics, err := io.ReadAll(res.Body)
+	suite.Require().NoError(err)
+	suite.Contains(string(metrics), `access_control_denied_requests_total{client="anonymous",reason="unauthenticated"}`)
+}
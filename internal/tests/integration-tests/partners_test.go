@@ -1,7 +1,40 @@
 package integration_test
 
-import "log"
+import (
+	"net/http"
 
-func (s *IntegrationSuite) TestGetVehicles() {
-	log.Printf("failed to load configuration")
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+)
+
+func (suite *IntegrationSuite) TestGetUserPartners() {
+	var response v1.UserResponse
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	user := response.Data
+	suite.Equal("jdoe", user.CDSID)
+	suite.Equal("jdoe@volvocars.biz", user.Email)
+	suite.Empty(user.UnresolvedPartners)
+
+	partners := make(map[string]v1.Partner, len(user.Partners))
+	for _, partner := range user.Partners {
+		partners[partner.Type] = partner
+	}
+
+	suite.Require().Len(partners, 2)
+	suite.Equal("10001", partners["PARMA"].ParmaPartnerCode)
+	suite.Equal("nsc-se", partners["PARMA"].DistributorID)
+	suite.Equal("nsc-se", partners["NSC"].ID)
+	suite.Equal("SE", partners["NSC"].Market)
+}
+
+func (suite *IntegrationSuite) TestGetUserUnresolvedPartners() {
+	var response v1.UserResponse
+	res, err := suite.requester.DoRequest("v1/iam/users/jsmith", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Len(response.Data.Partners, 1)
+	suite.Equal([]string{"99999"}, response.Data.UnresolvedPartners)
 }
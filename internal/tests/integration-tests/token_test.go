@@ -0,0 +1,97 @@
+package integration_test
+
+import (
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+	"github.com/volvo-cars/connect-access-control/internal/pkg/auth"
+)
+
+func (suite *IntegrationSuite) TestPostToken() {
+	var response v1.TokenResponse
+	res, err := suite.requester.DoRequest("v1/iam/token", http.MethodPost, nil, &response, bearer(suite.token("jdoe", nil)))
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+	suite.Equal("Bearer", response.Data.TokenType)
+	suite.Positive(response.Data.ExpiresIn)
+
+	var jwks auth.JWKS
+	res, err = suite.requester.DoRequest(".well-known/jwks.json", http.MethodGet, nil, &jwks, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+	suite.Require().Len(jwks.Keys, 1)
+
+	key, err := jwks.Keys[0].PublicKey()
+	suite.Require().NoError(err)
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(response.Data.AccessToken, claims, func(t *jwt.Token) (any, error) {
+		suite.Equal(jwks.Keys[0].Kid, t.Header["kid"])
+		return key, nil
+	}, jwt.WithIssuer(mintedIssuer), jwt.WithAudience("user-portal"), jwt.WithExpirationRequired())
+	suite.Require().NoError(err)
+	suite.True(token.Valid)
+
+	suite.Equal("jdoe", claims["sub"])
+	suite.Equal("user-portal", claims["azp"])
+
+	access, ok := claims["access"].([]any)
+	suite.Require().True(ok)
+	suite.NotEmpty(access)
+
+	first, ok := access[0].(map[string]any)
+	suite.Require().True(ok)
+	suite.Contains(first, "context")
+	suite.Contains(first, "permission_groups")
+}
+
+func (suite *IntegrationSuite) TestPostTokenScopeNotAllowed() {
+	var response v1.ErrorResponse
+	token := suite.token("jdoe", jwt.MapClaims{"azp": "dealer-app"})
+	res, err := suite.requester.DoRequest("v1/iam/token", http.MethodPost, v1.TokenRequest{Scopes: []string{"user-admin"}}, &response, bearer(token))
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusForbidden, res.StatusCode)
+	suite.Equal(v1.ReasonScopeNotAllowed, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestPostTokenUnauthenticated() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/token", http.MethodPost, nil, &response, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusUnauthorized, res.StatusCode)
+	suite.Equal(v1.ReasonUnauthenticated, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestPostTokenOfOtherUser() {
+	// the own CDSID of the caller needs no privilege
+	res, err := suite.requester.DoRequest("v1/iam/token", http.MethodPost, v1.TokenRequest{CDSID: "jdoe"}, nil, bearer(suite.token("jdoe", nil)))
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, res.StatusCode)
+
+	var response v1.ErrorResponse
+	res, err = suite.requester.DoRequest("v1/iam/token", http.MethodPost, v1.TokenRequest{CDSID: "jsmith"}, &response, bearer(suite.token("jdoe", nil)))
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusForbidden, res.StatusCode)
+	suite.Equal(v1.ReasonClientNotPrivileged, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestPostTokenWithoutClient() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/token", http.MethodPost, nil, &response, bearer(suite.token("jdoe", jwt.MapClaims{"azp": nil})))
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusUnauthorized, res.StatusCode)
+	suite.Equal(v1.ReasonUnauthenticated, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestPostTokenWithUnresolvedCDSID() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/token", http.MethodPost, nil, &response, bearer(suite.token("knoid", nil)))
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(v1.ReasonCDSIDUnresolved, response.Error.Reason)
+}
@@ -0,0 +1,267 @@
+package integration_test
+
+import (
+	"net/http"
+
+	v1 "github.com/volvo-cars/connect-access-control/internal/api/v1"
+)
+
+func (suite *IntegrationSuite) TestGetUserNotFound() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/users/unknown", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(v1.ReasonUserNotFound, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestGetUserAccess() {
+	var response v1.Response[[]v1.UserAccess]
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe/access?scope=user-admin", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	accesses := make(map[string]v1.UserAccess, len(response.Data))
+	for _, access := range response.Data {
+		accesses[access.Context.Type] = access
+	}
+
+	suite.Require().Len(accesses, 2)
+	suite.ElementsMatch([]string{"view_user_details", "manage_user_details"}, accesses["PARMA"].PermissionGroups["user-admin"])
+	suite.ElementsMatch([]string{"view_user_details", "assign_admin_rights"}, accesses["NSC"].PermissionGroups["user-admin"])
+}
+
+func (suite *IntegrationSuite) TestFindUserByEmail() {
+	var response v1.UserResponse
+	res, err := suite.requester.DoRequest("v1/iam/users?email=JDOE@volvocars.biz", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Equal("jdoe", response.Data.CDSID)
+	suite.Len(response.Data.Partners, 2)
+}
+
+func (suite *IntegrationSuite) TestFindUserByUserID() {
+	var response v1.UserResponse
+	res, err := suite.requester.DoRequest("v1/iam/users?user_id=7c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Equal("jsmith", response.Data.CDSID)
+	suite.False(response.Data.CDSIDUnresolved)
+}
+
+func (suite *IntegrationSuite) TestFindUserWithUnresolvedCDSID() {
+	var response v1.UserResponse
+	res, err := suite.requester.DoRequest("v1/iam/users?user_id=9e8f7a6b-5c4d-4e3f-8a2b-1c0d9e8f7a6b", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Empty(response.Data.CDSID)
+	suite.True(response.Data.CDSIDUnresolved)
+}
+
+func (suite *IntegrationSuite) TestFindUserAccessByIdentity() {
+	var response v1.Response[[]v1.UserAccess]
+	path := "v1/iam/users/access?provider=AzureAD_VCC&provider_user_id=0f9e8d7c-6b5a-4321-8765-43210fedcba9&scope=user-admin"
+	res, err := suite.requester.DoRequest(path, http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Len(response.Data, 2)
+}
+
+func (suite *IntegrationSuite) TestFindUserInvalidLookup() {
+	for _, path := range []string{
+		"v1/iam/users",
+		"v1/iam/users?email=jdoe@volvocars.biz&user_id=5b0c6f7e-2d1a-4b8e-9f3c-1a2b3c4d5e6f",
+		"v1/iam/users?provider=AzureAD_VCC",
+	} {
+		res, err := suite.requester.DoRequest(path, http.MethodGet, nil, nil, nil)
+		suite.Require().NoError(err)
+		suite.Equal(http.StatusBadRequest, res.StatusCode, path)
+	}
+}
+
+func (suite *IntegrationSuite) TestFindUserPathTraversal() {
+	for _, path := range []string{
+		"v1/iam/users?user_id=../../roles",
+		"v1/iam/users?cdsid=../admin/secret",
+		"v1/iam/users/access?cdsid=..%2Froles&scope=user-admin",
+	} {
+		res, err := suite.requester.DoRequest(path, http.MethodGet, nil, nil, nil)
+		suite.Require().NoError(err)
+		suite.Equal(http.StatusBadRequest, res.StatusCode, path)
+	}
+
+	// the lookups of path parameters are rejected by the PLUMS gateway
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/users/..%5Croles", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusBadRequest, res.StatusCode)
+	suite.Equal(v1.ReasonInvalidUser, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestFindUserByEmailNotFound() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/users?email=nobody@volvocars.biz", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(v1.ReasonUserNotFound, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestGetUserIdentities() {
+	var response v1.UserResponse
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Equal([]v1.Identity{{
+		Provider:       "AzureAD_VCC",
+		ProviderUserID: "0f9e8d7c-6b5a-4321-8765-43210fedcba9",
+		AccountName:    "jdoe@volvocars.biz",
+	}}, response.Data.Identities)
+}
+
+func (suite *IntegrationSuite) TestGetUserProfile() {
+	var response v1.UserResponse
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Equal("Jane", response.Data.FirstName)
+	suite.Equal("Doe", response.Data.LastName)
+
+	for _, partner := range response.Data.Partners {
+		if partner.Type != "PARMA" {
+			continue
+		}
+
+		suite.True(partner.IsPrimary)
+		suite.Require().NotNil(partner.Address)
+		suite.Equal("Gothenburg", partner.Address.City)
+	}
+}
+
+func (suite *IntegrationSuite) TestGetUserFields() {
+	var response v1.Response[map[string]any]
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe?fields=cdsid,partners.id,partners.address.city", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	suite.Len(response.Data, 2)
+	suite.Equal("jdoe", response.Data["cdsid"])
+
+	partners, ok := response.Data["partners"].([]any)
+	suite.Require().True(ok)
+	for _, p := range partners {
+		partner := p.(map[string]any)
+		suite.Contains(partner, "id")
+		suite.NotContains(partner, "name")
+		if address, ok := partner["address"].(map[string]any); ok {
+			suite.Equal(map[string]any{"city": "Gothenburg"}, address)
+		}
+	}
+}
+
+func (suite *IntegrationSuite) TestGetUserUnknownField() {
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe?fields=cdsid,password", http.MethodGet, nil, nil, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusBadRequest, res.StatusCode)
+}
+
+func (suite *IntegrationSuite) TestGetUserAccessContext() {
+	for query, contextType := range map[string]string{
+		"context=10001":  "PARMA",
+		"context=nsc-se": "NSC",
+		"context=d6a1c2b3-0000-4000-8000-000000010001": "PARMA",
+		"primary=true": "PARMA",
+	} {
+		var response v1.Response[[]v1.UserAccess]
+		res, err := suite.requester.DoRequest("v1/iam/users/jdoe/access?scope=user-admin&"+query, http.MethodGet, nil, &response, nil)
+		suite.Require().NoError(err)
+		suite.Require().Equal(http.StatusOK, res.StatusCode, query)
+
+		suite.Require().Len(response.Data, 1, query)
+		suite.Equal(contextType, response.Data[0].Context.Type, query)
+	}
+}
+
+func (suite *IntegrationSuite) TestGetUserAccessContextNotFound() {
+	var response v1.ErrorResponse
+	res, err := suite.requester.DoRequest("v1/iam/users/jsmith/access?scope=user-admin&primary=true", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(v1.ReasonContextNotFound, response.Error.Reason)
+
+	res, err = suite.requester.DoRequest("v1/iam/users/jdoe/access?scope=user-admin&context=unknown", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(v1.ReasonContextNotFound, response.Error.Reason)
+}
+
+func (suite *IntegrationSuite) TestGetUserAccessDomains() {
+	var response v1.Response[[]v1.UserAccess]
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe/access?scope=user-admin&scope=retail-data", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	accesses := make(map[string]v1.UserAccess, len(response.Data))
+	for _, access := range response.Data {
+		accesses[access.Context.Type] = access
+	}
+
+	suite.Require().Len(accesses, 2)
+	suite.NotContains(accesses["PARMA"].PermissionGroups, "retail-data")
+	suite.Equal(v1.AccessDomain{Partners: []string{"d6a1c2b3-0000-4000-8000-000000010001"}}, accesses["PARMA"].Domains["retail-data"])
+	suite.Equal(v1.AccessDomain{Distributors: []string{"nsc-se"}, Markets: []string{"SE"}}, accesses["NSC"].Domains["retail-data"])
+	suite.NotEmpty(accesses["NSC"].PermissionGroups["user-admin"])
+}
+
+func (suite *IntegrationSuite) TestGetUserAccessInherited() {
+	var response v1.Response[[]v1.UserAccess]
+	res, err := suite.requester.DoRequest("v1/iam/users/jdoe/access?scope=retail-admin", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	inherited := make(map[string]v1.UserAccess)
+	for _, access := range response.Data {
+		if !access.Context.Inherited {
+			suite.Equal("nsc-se", access.Context.ID)
+			continue
+		}
+
+		suite.Equal("nsc-se", access.Context.InheritedFrom)
+		inherited[access.Context.Tag] = access
+	}
+
+	// 10001 is an own partner of the user without access of its own in the scope, it is not inherited either
+	suite.Require().Len(inherited, 1)
+	suite.NotContains(inherited, "10001")
+	suite.Equal("d6a1c2b3-0000-4000-8000-000000010002", inherited["10002"].Context.ID)
+	suite.Equal([]string{"manage_retailer_users"}, inherited["10002"].PermissionGroups["retail-admin"])
+
+	// a partner that is an own context of the user is not inherited as well
+	res, err = suite.requester.DoRequest("v1/iam/users/jdoe/access?scope=retail-admin&scope=user-admin", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+
+	contexts := make(map[string]bool)
+	for _, access := range response.Data {
+		suite.False(contexts[access.Context.ID], access.Context.ID)
+		contexts[access.Context.ID] = true
+	}
+	suite.Contains(contexts, "d6a1c2b3-0000-4000-8000-000000010001")
+	suite.Contains(contexts, "d6a1c2b3-0000-4000-8000-000000010002")
+
+	// inherited contexts are not evaluated for a context selection
+	res, err = suite.requester.DoRequest("v1/iam/users/jdoe/access?scope=retail-admin&context=nsc-se", http.MethodGet, nil, &response, nil)
+	suite.Require().NoError(err)
+	suite.Require().Equal(http.StatusOK, res.StatusCode)
+	suite.Require().Len(response.Data, 1)
+	suite.False(response.Data[0].Context.Inherited)
+}
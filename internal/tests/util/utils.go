@@ -13,6 +13,7 @@ import (
 
 type TestResponse struct {
 	StatusCode int
+	Header     http.Header
 	Body       any
 }
 
@@ -65,6 +66,7 @@ func (r *Requester) DoRequest(path, method string, body, response any, headers m
 
 	return TestResponse{
 		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
 		Body:       response,
 	}, nil
 }